@@ -0,0 +1,213 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/fingerprint"
+	"github.com/go-task/task/v3/internal/hash"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// affectedChecker finds out whether tasks are affected by the files changed
+// since [Executor.AffectedSince]. The results are memoized by the hash of the
+// compiled task, so tasks shared by several dependency trees are only checked
+// once for the same variables.
+type affectedChecker struct {
+	e        *Executor
+	changed  map[string]bool
+	results  map[string]bool
+	visiting map[string]bool
+	cut      bool
+}
+
+func (e *Executor) newAffectedChecker() (*affectedChecker, error) {
+	e.changedFilesOnce.Do(func() {
+		e.changedFiles, e.changedFilesErr = changedFilesSince(context.Background(), e.Dir, e.AffectedSince)
+	})
+	if e.changedFilesErr != nil {
+		return nil, e.changedFilesErr
+	}
+	return &affectedChecker{
+		e:        e,
+		changed:  e.changedFiles,
+		results:  make(map[string]bool),
+		visiting: make(map[string]bool),
+	}, nil
+}
+
+// filterAffectedCalls returns the calls whose task is affected by the files
+// changed since [Executor.AffectedSince].
+func (e *Executor) filterAffectedCalls(calls []*Call) ([]*Call, error) {
+	checker, err := e.newAffectedChecker()
+	if err != nil {
+		return nil, err
+	}
+	affectedCalls := make([]*Call, 0, len(calls))
+	for _, call := range calls {
+		affected, err := checker.isAffected(call)
+		if err != nil {
+			return nil, err
+		}
+		if !affected {
+			e.Logger.VerboseErrf(logger.Magenta, "task: Task %q is not affected by the changes since %q\n", call.Task, e.AffectedSince)
+			continue
+		}
+		affectedCalls = append(affectedCalls, call)
+	}
+	if len(affectedCalls) == 0 {
+		e.Logger.Errf(logger.Yellow, "task: No task affected by the changes since %q\n", e.AffectedSince)
+	}
+	return affectedCalls, nil
+}
+
+// filterOutUnaffected returns a [FilterFunc] that filters out the tasks that
+// are not affected by the files changed since [Executor.AffectedSince].
+func (e *Executor) filterOutUnaffected() (FilterFunc, error) {
+	checker, err := e.newAffectedChecker()
+	if err != nil {
+		return nil, err
+	}
+	affected := make(map[string]bool)
+	for task := range e.Taskfile.Tasks.Values(nil) {
+		if task.Internal {
+			continue
+		}
+		isAffected, err := checker.isAffected(&Call{Task: task.Task})
+		if err != nil {
+			// Keep listing the task, since it can't be told whether it is affected
+			e.Logger.VerboseErrf(logger.Yellow, "task: Unable to check whether task %q is affected: %v\n", task.Task, err)
+			isAffected = true
+		}
+		affected[task.Task] = isAffected
+	}
+	return func(task *ast.Task) bool {
+		return !affected[task.Task]
+	}, nil
+}
+
+// isAffected returns true if one of the sources of the called task, or of the
+// tasks it calls, changed.
+func (c *affectedChecker) isAffected(call *Call) (bool, error) {
+	t, err := c.e.CompiledTask(call)
+	if err != nil {
+		return false, err
+	}
+	key, err := hash.Hash(t)
+	if err != nil {
+		return false, err
+	}
+	if affected, ok := c.results[key]; ok {
+		return affected, nil
+	}
+	// A task calling back a task being checked is cut short. The unaffected
+	// results depending on such a cut are not final until the task that
+	// started the check is done, so they are not memoized.
+	if c.visiting[key] {
+		c.cut = true
+		return false, nil
+	}
+	c.visiting[key] = true
+	cut := c.cut
+	c.cut = false
+
+	affected, err := c.checkAffected(t)
+	delete(c.visiting, key)
+	if err != nil {
+		return false, err
+	}
+	if affected || !c.cut || len(c.visiting) == 0 {
+		c.results[key] = affected
+	}
+	c.cut = c.cut || cut
+	return affected, nil
+}
+
+func (c *affectedChecker) checkAffected(t *ast.Task) (bool, error) {
+	if len(t.Sources) > 0 {
+		dir := t.Dir
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = resolved
+		}
+		// The changed files are matched against the globs rather than the
+		// existing sources, so that deleted sources are taken into account
+		isSource := fingerprint.Matcher(dir, t.Sources)
+		for changed := range c.changed {
+			if isSource(changed) {
+				return true, nil
+			}
+		}
+	}
+
+	var calls []*Call
+	for _, dep := range t.Deps {
+		if dep != nil && dep.Task != "" {
+			calls = append(calls, &Call{Task: dep.Task, Vars: dep.Vars, Silent: dep.Silent})
+		}
+	}
+	for _, cmd := range t.Cmds {
+		if cmd != nil && cmd.Task != "" {
+			calls = append(calls, &Call{Task: cmd.Task, Vars: cmd.Vars, Silent: cmd.Silent})
+		}
+	}
+	for _, call := range calls {
+		affected, err := c.isAffected(call)
+		if err != nil {
+			return false, err
+		}
+		if affected {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// changedFilesSince returns the absolute paths of the files of the git
+// repository of the given directory that changed since its merge base with the
+// given ref, including the uncommitted and untracked files.
+func changedFilesSince(ctx context.Context, dir, ref string) (map[string]bool, error) {
+	root, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	base, err := git(ctx, dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := git(ctx, root, "diff", "--name-only", "--no-renames", "-z", base)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git(ctx, root, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]bool)
+	for _, name := range strings.Split(diff+"\x00"+untracked, "\x00") {
+		if name == "" {
+			continue
+		}
+		changed[filepath.Join(root, filepath.FromSlash(name))] = true
+	}
+	return changed, nil
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("task: git %s failed: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
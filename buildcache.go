@@ -0,0 +1,125 @@
+package task
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-task/task/v3/internal/fingerprint"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// buildCacheKey returns the key under which the files generated by the given
+// task are stored in the build cache. The key changes whenever the sources,
+// the commands or the variables and environment declared in the Taskfile
+// change. An empty key is returned if the task does not use the build cache.
+//
+// The key doesn't depend on where the project is checked out: paths are
+// relative to the root directory, so that checkouts in different directories
+// share the same entries.
+func (e *Executor) buildCacheKey(t *ast.Task, call *Call) string {
+	if !t.Cache || e.Dry || e.buildCache == nil {
+		return ""
+	}
+	origTask, err := e.GetTask(call)
+	if err != nil {
+		return ""
+	}
+	sources, err := fingerprint.NewChecksumChecker(e.TempDir.Fingerprint, true).SourceChecksums(t)
+	if err != nil {
+		e.Logger.VerboseErrf(logger.Yellow, "task: unable to compute the cache key of %q: %v\n", t.Name(), err)
+		return ""
+	}
+
+	// Compiled values often contain absolute paths, like ROOT_DIR
+	relative := strings.NewReplacer(e.Dir, "{{.ROOT_DIR}}")
+	dir, err := filepath.Rel(e.Dir, t.Dir)
+	if err != nil {
+		dir = relative.Replace(t.Dir)
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "task %s\ndir %s\n", t.Task, filepath.ToSlash(dir))
+	for _, path := range slices.Sorted(maps.Keys(sources)) {
+		fmt.Fprintf(h, "source %s %s\n", path, sources[path])
+	}
+	for _, g := range t.Generates {
+		fmt.Fprintf(h, "generates %t %s\n", g.Negate, relative.Replace(g.Glob))
+	}
+	for _, cmd := range t.Cmds {
+		fmt.Fprintf(h, "cmd %q %q %t\n", relative.Replace(cmd.Cmd), cmd.Task, cmd.Defer)
+		writeVars(h, relative, "cmd var", cmd.Vars)
+	}
+	writeVars(h, relative, "env", t.Env)
+
+	// Only variables declared in the Taskfile are part of the key, as the
+	// compiled variables also contain the whole environment
+	var names []string
+	for _, vars := range []*ast.Vars{e.Taskfile.Vars, origTask.IncludedTaskfileVars, origTask.IncludeVars, origTask.Vars, call.Vars} {
+		for name := range vars.Keys() {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range slices.Compact(names) {
+		if v, ok := t.Vars.Get(name); ok {
+			fmt.Fprintf(h, "var %s %s\n", name, relative.Replace(fmt.Sprint(v.Value)))
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+func writeVars(w io.Writer, relative *strings.Replacer, kind string, vars *ast.Vars) {
+	for name, v := range vars.All() {
+		fmt.Fprintf(w, "%s %s %s\n", kind, name, relative.Replace(fmt.Sprint(v.Value)))
+	}
+}
+
+// restoreFromBuildCache restores the files generated by the given task from
+// the build cache. It returns true if they were restored, in which case the
+// commands of the task don't need to run.
+func (e *Executor) restoreFromBuildCache(ctx context.Context, t *ast.Task, call *Call, key string) bool {
+	if key == "" {
+		return false
+	}
+	restored, err := e.buildCache.Restore(ctx, key, t.Dir)
+	if err != nil {
+		e.Logger.Errf(logger.Yellow, "task: unable to restore %q from the build cache: %v\n", t.Name(), err)
+		return false
+	}
+	if !restored {
+		e.Logger.VerboseErrf(logger.Magenta, "task: Task %q is not in the build cache\n", t.Name())
+		return false
+	}
+	if e.Verbose || (!call.Silent && !t.IsSilent() && !e.Taskfile.Silent && !e.Silent) {
+		name := t.Name()
+		if e.OutputStyle.Name == "prefixed" {
+			name = t.Prefix
+		}
+		e.Logger.Errf(logger.Magenta, "task: Task %q restored from the build cache\n", name)
+	}
+	return true
+}
+
+// saveToBuildCache stores the files generated by the given task in the build
+// cache. Failures are only reported, since the task itself succeeded.
+func (e *Executor) saveToBuildCache(ctx context.Context, t *ast.Task, key string) {
+	if key == "" {
+		return
+	}
+	files, err := fingerprint.Globs(t.Dir, t.Generates)
+	if err == nil {
+		err = e.buildCache.Save(ctx, key, t.Dir, files)
+	}
+	if err != nil {
+		e.Logger.Errf(logger.Yellow, "task: unable to save %q to the build cache: %v\n", t.Name(), err)
+		return
+	}
+	e.Logger.VerboseErrf(logger.Magenta, "task: Task %q saved to the build cache\n", t.Name())
+}
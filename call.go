@@ -8,4 +8,5 @@ type Call struct {
 	Vars     *ast.Vars
 	Silent   bool
 	Indirect bool // True if the task was called by another task
+	Attempt  int  // The current retry attempt, starting at 1 (0 means 1)
 }
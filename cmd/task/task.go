@@ -17,6 +17,7 @@ import (
 	"github.com/go-task/task/v3/internal/flags"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/version"
+	"github.com/go-task/task/v3/taskfile"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
@@ -117,6 +118,26 @@ func run() error {
 		return nil
 	}
 
+	if flags.Sign {
+		args, _, err := args.Get()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return errors.New("task: No Taskfile to sign")
+		}
+		for _, path := range args {
+			sigPath, err := taskfile.Sign(path, flags.SigningKey)
+			if err != nil {
+				return err
+			}
+			if !flags.Silent {
+				log.Outf(logger.Green, "Signature created: %s\n", filepathext.TryAbsToRel(sigPath))
+			}
+		}
+		return nil
+	}
+
 	if flags.Completion != "" {
 		script, err := task.Completion(flags.Completion)
 		if err != nil {
@@ -126,6 +147,21 @@ func run() error {
 		return nil
 	}
 
+	if flags.Cache != "" || flags.ClearCache {
+		e := task.NewExecutor(flags.WithFlags())
+		if err := e.SetupRemoteCache(); err != nil {
+			return err
+		}
+		switch flags.Cache {
+		case "list":
+			return e.ListRemoteCache(context.Background())
+		case "prune":
+			return e.PruneRemoteCache(context.Background())
+		default:
+			return e.ClearRemoteCache()
+		}
+	}
+
 	e := task.NewExecutor(
 		flags.WithFlags(),
 		task.WithVersionCheck(true),
@@ -134,9 +170,10 @@ func run() error {
 		return err
 	}
 
-	if flags.ClearCache {
-		cachePath := filepath.Join(e.TempDir.Remote, "remote")
-		return os.RemoveAll(cachePath)
+	// The lock file and the vendored Taskfiles are written while reading the
+	// Taskfiles
+	if flags.Lock || flags.UpdateLock || flags.Vendor {
+		return nil
 	}
 
 	listOptions := task.NewListOptions(
@@ -199,5 +236,13 @@ func run() error {
 		return e.Status(ctx, calls...)
 	}
 
+	if flags.Why {
+		return e.Why(ctx, calls...)
+	}
+
+	if flags.Plan {
+		return e.PrintPlan(ctx, flags.ListJson, calls...)
+	}
+
 	return e.Run(ctx, calls...)
 }
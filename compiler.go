@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -213,16 +214,20 @@ func (c *Compiler) getSpecialVars(t *ast.Task, call *Call) (map[string]string, e
 		allVars["TASK_DIR"] = filepathext.SmartJoin(c.Dir, t.Dir)
 		allVars["TASKFILE"] = t.Location.Taskfile
 		allVars["TASKFILE_DIR"] = filepath.Dir(t.Location.Taskfile)
+		allVars["TASKFILE_COMMIT"] = t.Location.Commit
 	} else {
 		allVars["TASK"] = ""
 		allVars["TASK_DIR"] = ""
 		allVars["TASKFILE"] = ""
 		allVars["TASKFILE_DIR"] = ""
+		allVars["TASKFILE_COMMIT"] = ""
 	}
 	if call != nil {
 		allVars["ALIAS"] = call.Task
+		allVars["TASK_ATTEMPT"] = strconv.Itoa(max(call.Attempt, 1))
 	} else {
 		allVars["ALIAS"] = ""
+		allVars["TASK_ATTEMPT"] = ""
 	}
 
 	return allVars, nil
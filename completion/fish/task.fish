@@ -67,6 +67,8 @@ complete -c $GO_TASK_PROGNAME \
 
 # Standard flags
 complete -c $GO_TASK_PROGNAME -s a -l list-all                  -d 'list all tasks'
+complete -c $GO_TASK_PROGNAME      -l build-cache-dir           -d 'directory of the build cache' -xa "(__fish_complete_directories)"
+complete -c $GO_TASK_PROGNAME      -l build-cache-url           -d 'URL of a shared build cache'
 complete -c $GO_TASK_PROGNAME -s c -l color                     -d 'colored output (default true)'
 complete -c $GO_TASK_PROGNAME -s C -l concurrency               -d 'limit number of concurrent tasks'
 complete -c $GO_TASK_PROGNAME      -l completion                -d 'generate shell completion script' -xa "bash zsh fish powershell"
@@ -91,14 +93,19 @@ complete -c $GO_TASK_PROGNAME -s o -l output                    -d 'set output s
 complete -c $GO_TASK_PROGNAME      -l output-group-begin        -d 'message template before grouped output'
 complete -c $GO_TASK_PROGNAME      -l output-group-end          -d 'message template after grouped output'
 complete -c $GO_TASK_PROGNAME      -l output-group-error-only   -d 'hide output from successful tasks'
+complete -c $GO_TASK_PROGNAME      -l plan                      -d 'show which tasks would run and why'
 complete -c $GO_TASK_PROGNAME -s p -l parallel                  -d 'execute tasks in parallel'
+complete -c $GO_TASK_PROGNAME -s r -l recursive                 -d 'execute tasks in every included namespace'
+complete -c $GO_TASK_PROGNAME      -l affected-since            -d 'only run tasks affected by changes since a git ref' -x
 complete -c $GO_TASK_PROGNAME -s s -l silent                    -d 'disable echoing'
 complete -c $GO_TASK_PROGNAME      -l sort                      -d 'set task sorting order' -xa "default alphanumeric none"
 complete -c $GO_TASK_PROGNAME      -l status                    -d 'exit non-zero if tasks not up-to-date'
 complete -c $GO_TASK_PROGNAME      -l summary                   -d 'show task summary'
+complete -c $GO_TASK_PROGNAME      -l task-timeout              -d 'default timeout for each task'
 complete -c $GO_TASK_PROGNAME -s t -l taskfile                  -d 'choose Taskfile to run'
 complete -c $GO_TASK_PROGNAME -s v -l verbose                   -d 'verbose output'
 complete -c $GO_TASK_PROGNAME      -l version                   -d 'show version'
+complete -c $GO_TASK_PROGNAME      -l why                       -d 'explain why tasks are not up-to-date'
 complete -c $GO_TASK_PROGNAME -s w -l watch                     -d 'watch mode, re-run on changes'
 complete -c $GO_TASK_PROGNAME -s y -l yes                       -d 'assume yes to all prompts'
 
@@ -114,7 +121,15 @@ complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES"
 complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l cacert           -d 'custom CA certificate for TLS' -r
 complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l cert             -d 'client certificate for mTLS' -r
 complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l cert-key         -d 'client certificate private key' -r
+complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l mirrors          -d 'mirrors of remote Taskfiles' -r
+complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l trusted-keys     -d 'trusted signing keys' -r
+complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l signing-key      -d 'private key for --sign' -r
 
 # RemoteTaskfiles experiment - Operations
 complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l download    -d 'download remote Taskfile'
+complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l cache       -d 'manage remote Taskfile cache' -xa 'list prune clear'
 complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l clear-cache -d 'clear remote Taskfile cache'
+complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l lock        -d 'write Taskfile.lock'
+complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l update-lock -d 'update Taskfile.lock'
+complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l vendor      -d 'vendor remote Taskfiles'
+complete -c $GO_TASK_PROGNAME -n "__task_is_experiment_enabled REMOTE_TASKFILES" -l sign        -d 'sign Taskfiles'
@@ -8,6 +8,8 @@ Register-ArgumentCompleter -CommandName task -ScriptBlock {
 			# Standard flags (alphabetical order)
 			[CompletionResult]::new('-a', '-a', [CompletionResultType]::ParameterName, 'list all tasks'),
 			[CompletionResult]::new('--list-all', '--list-all', [CompletionResultType]::ParameterName, 'list all tasks'),
+			[CompletionResult]::new('--build-cache-dir', '--build-cache-dir', [CompletionResultType]::ParameterName, 'build cache directory'),
+			[CompletionResult]::new('--build-cache-url', '--build-cache-url', [CompletionResultType]::ParameterName, 'shared build cache URL'),
 			[CompletionResult]::new('-c', '-c', [CompletionResultType]::ParameterName, 'colored output'),
 			[CompletionResult]::new('--color', '--color', [CompletionResultType]::ParameterName, 'colored output'),
 			[CompletionResult]::new('-C', '-C', [CompletionResultType]::ParameterName, 'limit concurrent tasks'),
@@ -46,13 +48,18 @@ Register-ArgumentCompleter -CommandName task -ScriptBlock {
 			[CompletionResult]::new('--output-group-begin', '--output-group-begin', [CompletionResultType]::ParameterName, 'template before group'),
 			[CompletionResult]::new('--output-group-end', '--output-group-end', [CompletionResultType]::ParameterName, 'template after group'),
 			[CompletionResult]::new('--output-group-error-only', '--output-group-error-only', [CompletionResultType]::ParameterName, 'hide successful output'),
+			[CompletionResult]::new('--plan', '--plan', [CompletionResultType]::ParameterName, 'show execution plan'),
 			[CompletionResult]::new('-p', '-p', [CompletionResultType]::ParameterName, 'execute in parallel'),
 			[CompletionResult]::new('--parallel', '--parallel', [CompletionResultType]::ParameterName, 'execute in parallel'),
+			[CompletionResult]::new('-r', '-r', [CompletionResultType]::ParameterName, 'execute in every namespace'),
+			[CompletionResult]::new('--recursive', '--recursive', [CompletionResultType]::ParameterName, 'execute in every namespace'),
+			[CompletionResult]::new('--affected-since', '--affected-since', [CompletionResultType]::ParameterName, 'only affected tasks'),
 			[CompletionResult]::new('-s', '-s', [CompletionResultType]::ParameterName, 'silent mode'),
 			[CompletionResult]::new('--silent', '--silent', [CompletionResultType]::ParameterName, 'silent mode'),
 			[CompletionResult]::new('--sort', '--sort', [CompletionResultType]::ParameterName, 'task sorting order'),
 			[CompletionResult]::new('--status', '--status', [CompletionResultType]::ParameterName, 'check task status'),
 			[CompletionResult]::new('--summary', '--summary', [CompletionResultType]::ParameterName, 'show task summary'),
+			[CompletionResult]::new('--task-timeout', '--task-timeout', [CompletionResultType]::ParameterName, 'default task timeout'),
 			[CompletionResult]::new('-t', '-t', [CompletionResultType]::ParameterName, 'choose Taskfile'),
 			[CompletionResult]::new('--taskfile', '--taskfile', [CompletionResultType]::ParameterName, 'choose Taskfile'),
 			[CompletionResult]::new('-v', '-v', [CompletionResultType]::ParameterName, 'verbose output'),
@@ -60,6 +67,7 @@ Register-ArgumentCompleter -CommandName task -ScriptBlock {
 			[CompletionResult]::new('--version', '--version', [CompletionResultType]::ParameterName, 'show version'),
 			[CompletionResult]::new('-w', '-w', [CompletionResultType]::ParameterName, 'watch mode'),
 			[CompletionResult]::new('--watch', '--watch', [CompletionResultType]::ParameterName, 'watch mode'),
+			[CompletionResult]::new('--why', '--why', [CompletionResultType]::ParameterName, 'explain task status'),
 			[CompletionResult]::new('-y', '-y', [CompletionResultType]::ParameterName, 'assume yes'),
 			[CompletionResult]::new('--yes', '--yes', [CompletionResultType]::ParameterName, 'assume yes')
 		)
@@ -80,9 +88,17 @@ Register-ArgumentCompleter -CommandName task -ScriptBlock {
 			$completions += [CompletionResult]::new('--cacert', '--cacert', [CompletionResultType]::ParameterName, 'custom CA certificate')
 			$completions += [CompletionResult]::new('--cert', '--cert', [CompletionResultType]::ParameterName, 'client certificate')
 			$completions += [CompletionResult]::new('--cert-key', '--cert-key', [CompletionResultType]::ParameterName, 'client private key')
+			$completions += [CompletionResult]::new('--mirrors', '--mirrors', [CompletionResultType]::ParameterName, 'mirrors of remote Taskfiles')
+			$completions += [CompletionResult]::new('--trusted-keys', '--trusted-keys', [CompletionResultType]::ParameterName, 'trusted signing keys')
+			$completions += [CompletionResult]::new('--signing-key', '--signing-key', [CompletionResultType]::ParameterName, 'private key for --sign')
 			# Operations
 			$completions += [CompletionResult]::new('--download', '--download', [CompletionResultType]::ParameterName, 'download remote Taskfile')
+			$completions += [CompletionResult]::new('--cache', '--cache', [CompletionResultType]::ParameterName, 'manage remote cache')
 			$completions += [CompletionResult]::new('--clear-cache', '--clear-cache', [CompletionResultType]::ParameterName, 'clear cache')
+			$completions += [CompletionResult]::new('--lock', '--lock', [CompletionResultType]::ParameterName, 'write Taskfile.lock')
+			$completions += [CompletionResult]::new('--update-lock', '--update-lock', [CompletionResultType]::ParameterName, 'update Taskfile.lock')
+			$completions += [CompletionResult]::new('--vendor', '--vendor', [CompletionResultType]::ParameterName, 'vendor remote Taskfiles')
+			$completions += [CompletionResult]::new('--sign', '--sign', [CompletionResultType]::ParameterName, 'sign Taskfiles')
 		}
 
 		return $completions.Where{ $_.CompletionText.StartsWith($commandName) }
@@ -27,6 +27,8 @@ const (
 	CodeTaskfileInvalid
 	CodeTaskfileCycle
 	CodeTaskfileDoesNotMatchChecksum
+	CodeTaskfileNotLocked
+	CodeTaskfileSignature
 )
 
 // Task related exit codes
@@ -39,6 +41,7 @@ const (
 	CodeTaskCancelled
 	CodeTaskMissingRequiredVars
 	CodeTaskNotAllowedVars
+	CodeTaskTimeout
 )
 
 // TaskError extends the standard error interface with a Code method. This code will
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"mvdan.cc/sh/v3/interp"
 )
@@ -43,6 +44,10 @@ func (err *TaskRunError) Error() string {
 }
 
 func (err *TaskRunError) Code() int {
+	var timeoutErr *TaskTimeoutError
+	if errors.As(err.Err, &timeoutErr) {
+		return timeoutErr.Code()
+	}
 	return CodeTaskRunError
 }
 
@@ -206,3 +211,22 @@ func (err *TaskNotAllowedVarsError) Error() string {
 func (err *TaskNotAllowedVarsError) Code() int {
 	return CodeTaskNotAllowedVars
 }
+
+// TaskTimeoutError is returned when a task or one of its commands runs for
+// longer than its configured timeout.
+type TaskTimeoutError struct {
+	TaskName string
+	Cmd      string
+	Timeout  time.Duration
+}
+
+func (err *TaskTimeoutError) Error() string {
+	if err.Cmd != "" {
+		return fmt.Sprintf(`task: Command %q in task %q timed out after %s`, err.Cmd, err.TaskName, err.Timeout)
+	}
+	return fmt.Sprintf(`task: Task %q timed out after %s`, err.TaskName, err.Timeout)
+}
+
+func (err *TaskTimeoutError) Code() int {
+	return CodeTaskTimeout
+}
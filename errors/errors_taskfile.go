@@ -196,22 +196,81 @@ func (err TaskfileCycleError) Code() int {
 }
 
 // TaskfileDoesNotMatchChecksum is returned when a Taskfile's checksum does not
-// match the one pinned in the parent Taskfile.
+// match the one pinned in the parent Taskfile or in the lock file.
 type TaskfileDoesNotMatchChecksum struct {
 	URI              string
 	ExpectedChecksum string
 	ActualChecksum   string
+	// Lockfile is the path of the lock file the expected checksum comes from,
+	// if any.
+	Lockfile string
 }
 
 func (err *TaskfileDoesNotMatchChecksum) Error() string {
-	return fmt.Sprintf(
+	msg := fmt.Sprintf(
 		"task: The checksum of the Taskfile at %q does not match!\ngot: %q\nwant: %q",
 		err.URI,
 		err.ActualChecksum,
 		err.ExpectedChecksum,
 	)
+	if err.Lockfile != "" {
+		msg += fmt.Sprintf("\nRun \"task --update-lock\" to update %q", err.Lockfile)
+	}
+	return msg
 }
 
 func (err *TaskfileDoesNotMatchChecksum) Code() int {
 	return CodeTaskfileDoesNotMatchChecksum
 }
+
+// TaskfileNotLockedError is returned when a lock file exists, but a remote
+// Taskfile is not listed in it.
+type TaskfileNotLockedError struct {
+	URI      string
+	Lockfile string
+}
+
+func (err *TaskfileNotLockedError) Error() string {
+	return fmt.Sprintf(
+		"task: The Taskfile at %q is not in %q. Run \"task --lock\" to add it",
+		err.URI,
+		err.Lockfile,
+	)
+}
+
+func (err *TaskfileNotLockedError) Code() int {
+	return CodeTaskfileNotLocked
+}
+
+// SignatureReason describes why the signature of a Taskfile was rejected.
+type SignatureReason int
+
+const (
+	SignatureMissing SignatureReason = iota
+	SignatureInvalid
+	SignatureUntrusted
+)
+
+// TaskfileSignatureError is returned when trusted keys are configured, but a
+// remote Taskfile is not signed, or not signed by one of them.
+type TaskfileSignatureError struct {
+	URI    string
+	Reason SignatureReason
+	// Key is the fingerprint of the key of untrusted signatures.
+	Key string
+}
+
+func (err *TaskfileSignatureError) Error() string {
+	switch err.Reason {
+	case SignatureInvalid:
+		return fmt.Sprintf("task: The signature of the Taskfile at %q is invalid", err.URI)
+	case SignatureUntrusted:
+		return fmt.Sprintf("task: The Taskfile at %q is signed by an untrusted key (%s)", err.URI, err.Key)
+	default:
+		return fmt.Sprintf("task: The Taskfile at %q is not signed", err.URI)
+	}
+}
+
+func (err *TaskfileSignatureError) Code() int {
+	return CodeTaskfileSignature
+}
@@ -10,9 +10,11 @@ import (
 	"github.com/puzpuzpuz/xsync/v4"
 	"github.com/sajari/fuzzy"
 
+	"github.com/go-task/task/v3/internal/buildcache"
 	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/internal/output"
 	"github.com/go-task/task/v3/internal/sort"
+	"github.com/go-task/task/v3/taskfile"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
@@ -34,11 +36,17 @@ type (
 		Insecure            bool
 		Download            bool
 		Offline             bool
+		Lock                bool
+		UpdateLock          bool
+		Vendor              bool
 		TrustedHosts        []string
+		TrustedKeys         []string
 		Timeout             time.Duration
 		CacheExpiryDuration time.Duration
 		RemoteCacheDir      string
 		CACert              string
+		Mirrors             map[string]string
+		Credentials         map[string]taskfile.Credentials
 		Cert                string
 		CertKey             string
 		Watch               bool
@@ -51,10 +59,16 @@ type (
 		Dry                 bool
 		Summary             bool
 		Parallel            bool
+		Recursive           bool
+		AffectedSince       string
 		Color               bool
 		Concurrency         int
 		Interval            time.Duration
 		Failfast            bool
+		TaskTimeout         time.Duration
+		BuildCacheDir       string
+		BuildCacheURL       string
+		BuildCacheToken     string
 
 		// I/O
 		Stdin  io.Reader
@@ -74,6 +88,10 @@ type (
 		fuzzyModel     *fuzzy.Model
 		fuzzyModelOnce sync.Once
 
+		changedFiles     map[string]bool
+		changedFilesErr  error
+		changedFilesOnce sync.Once
+
 		promptedVars         *ast.Vars // vars collected via interactive prompts
 		concurrencySemaphore chan struct{}
 		taskCallCount        map[string]*int32
@@ -81,6 +99,7 @@ type (
 		executionHashes      map[string]context.Context
 		executionHashesMutex sync.Mutex
 		watchedDirs          *xsync.Map[string, bool]
+		buildCache           *buildcache.Cache
 	}
 	TempDir struct {
 		Remote      string
@@ -249,6 +268,20 @@ func (o *trustedHostsOption) ApplyToExecutor(e *Executor) {
 	e.TrustedHosts = o.trustedHosts
 }
 
+// WithTrustedKeys configures the [Executor] with a list of public keys, in the
+// authorized_keys format, that remote Taskfiles must be signed with.
+func WithTrustedKeys(trustedKeys []string) ExecutorOption {
+	return &trustedKeysOption{trustedKeys}
+}
+
+type trustedKeysOption struct {
+	trustedKeys []string
+}
+
+func (o *trustedKeysOption) ApplyToExecutor(e *Executor) {
+	e.TrustedKeys = o.trustedKeys
+}
+
 // WithTimeout sets the [Executor]'s timeout for fetching remote taskfiles. By
 // default, the timeout is set to 10 seconds.
 func WithTimeout(timeout time.Duration) ExecutorOption {
@@ -303,6 +336,35 @@ func (o *caCertOption) ApplyToExecutor(e *Executor) {
 	e.CACert = o.caCert
 }
 
+// WithMirrors sets the URL prefixes of remote Taskfiles to replace with the
+// prefix of a mirror when downloading them. Remote Taskfiles are still cached
+// and trusted under their original URL.
+func WithMirrors(mirrors map[string]string) ExecutorOption {
+	return &mirrorsOption{mirrors}
+}
+
+type mirrorsOption struct {
+	mirrors map[string]string
+}
+
+func (o *mirrorsOption) ApplyToExecutor(e *Executor) {
+	e.Mirrors = o.mirrors
+}
+
+// WithCredentials sets the credentials of the hosts of remote Taskfiles, keyed
+// by host, optionally followed by a port.
+func WithCredentials(credentials map[string]taskfile.Credentials) ExecutorOption {
+	return &credentialsOption{credentials}
+}
+
+type credentialsOption struct {
+	credentials map[string]taskfile.Credentials
+}
+
+func (o *credentialsOption) ApplyToExecutor(e *Executor) {
+	e.Credentials = o.credentials
+}
+
 // WithCert sets the path to a client certificate for TLS connections.
 func WithCert(cert string) ExecutorOption {
 	return &certOption{cert: cert}
@@ -466,6 +528,35 @@ func (o *parallelOption) ApplyToExecutor(e *Executor) {
 	e.Parallel = o.parallel
 }
 
+// WithRecursive tells the [Executor] to run the tasks given in the same call
+// in every included namespace that defines them, in dependency order.
+func WithRecursive(recursive bool) ExecutorOption {
+	return &recursiveOption{recursive}
+}
+
+type recursiveOption struct {
+	recursive bool
+}
+
+func (o *recursiveOption) ApplyToExecutor(e *Executor) {
+	e.Recursive = o.recursive
+}
+
+// WithAffectedSince tells the [Executor] to only run and list the tasks whose
+// sources, or the sources of their dependencies, changed since the given git
+// ref.
+func WithAffectedSince(ref string) ExecutorOption {
+	return &affectedSinceOption{ref}
+}
+
+type affectedSinceOption struct {
+	ref string
+}
+
+func (o *affectedSinceOption) ApplyToExecutor(e *Executor) {
+	e.AffectedSince = o.ref
+}
+
 // WithColor tells the [Executor] whether or not to output using colorized
 // strings.
 func WithColor(color bool) ExecutorOption {
@@ -617,3 +708,105 @@ type failfastOption struct {
 func (o *failfastOption) ApplyToExecutor(e *Executor) {
 	e.Failfast = o.failfast
 }
+
+// WithTaskTimeout sets the default timeout of each task run by the
+// [Executor]. Tasks can override this with their own timeout. A zero value
+// disables the timeout.
+func WithTaskTimeout(timeout time.Duration) ExecutorOption {
+	return &taskTimeoutOption{timeout}
+}
+
+type taskTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (o *taskTimeoutOption) ApplyToExecutor(e *Executor) {
+	e.TaskTimeout = o.timeout
+}
+
+// WithBuildCacheDir sets the directory of the build cache, where the files
+// generated by tasks with "cache: true" are stored. By default, they are
+// stored in the "cache" directory of the temporary directory.
+func WithBuildCacheDir(dir string) ExecutorOption {
+	return &buildCacheDirOption{dir}
+}
+
+type buildCacheDirOption struct {
+	dir string
+}
+
+func (o *buildCacheDirOption) ApplyToExecutor(e *Executor) {
+	e.BuildCacheDir = o.dir
+}
+
+// WithBuildCacheURL sets the URL of an HTTP server that is used as a shared
+// build cache, in addition to the local one.
+func WithBuildCacheURL(url string) ExecutorOption {
+	return &buildCacheURLOption{url}
+}
+
+type buildCacheURLOption struct {
+	url string
+}
+
+func (o *buildCacheURLOption) ApplyToExecutor(e *Executor) {
+	e.BuildCacheURL = o.url
+}
+
+// WithBuildCacheToken sets the token sent as a bearer token to the HTTP server
+// of the shared build cache.
+func WithBuildCacheToken(token string) ExecutorOption {
+	return &buildCacheTokenOption{token}
+}
+
+type buildCacheTokenOption struct {
+	token string
+}
+
+func (o *buildCacheTokenOption) ApplyToExecutor(e *Executor) {
+	e.BuildCacheToken = o.token
+}
+
+// WithLock makes the [Executor] write the resolved URL, commit and checksum of
+// every remote Taskfile to the lock file, next to the root Taskfile. Remote
+// Taskfiles already in the lock file must still match it.
+func WithLock(lock bool) ExecutorOption {
+	return &lockOption{lock}
+}
+
+type lockOption struct {
+	lock bool
+}
+
+func (o *lockOption) ApplyToExecutor(e *Executor) {
+	e.Lock = o.lock
+}
+
+// WithUpdateLock makes the [Executor] write a new lock file from the latest
+// version of every remote Taskfile, ignoring the content of the current one.
+func WithUpdateLock(updateLock bool) ExecutorOption {
+	return &updateLockOption{updateLock}
+}
+
+type updateLockOption struct {
+	updateLock bool
+}
+
+func (o *updateLockOption) ApplyToExecutor(e *Executor) {
+	e.UpdateLock = o.updateLock
+}
+
+// WithVendor makes the [Executor] download every remote Taskfile into the
+// vendor directory, next to the root Taskfile. Vendored Taskfiles are then used
+// instead of the cache and the network.
+func WithVendor(vendor bool) ExecutorOption {
+	return &vendorOption{vendor}
+}
+
+type vendorOption struct {
+	vendor bool
+}
+
+func (o *vendorOption) ApplyToExecutor(e *Executor) {
+	e.Vendor = o.vendor
+}
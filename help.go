@@ -60,7 +60,15 @@ func (o ListOptions) Filters() []FilterFunc {
 // The function returns a boolean indicating whether tasks were found
 // and an error if one was encountered while preparing the output.
 func (e *Executor) ListTasks(o ListOptions) (bool, error) {
-	tasks, err := e.GetTaskList(o.Filters()...)
+	filters := o.Filters()
+	if e.AffectedSince != "" {
+		filter, err := e.filterOutUnaffected()
+		if err != nil {
+			return false, err
+		}
+		filters = append(filters, filter)
+	}
+	tasks, err := e.GetTaskList(filters...)
 	if err != nil {
 		return false, err
 	}
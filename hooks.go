@@ -0,0 +1,241 @@
+package task
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"mvdan.cc/sh/v3/interp"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/env"
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/slicesext"
+	"github.com/go-task/task/v3/internal/templater"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// hookContextKey marks the context of the tasks called by hooks, which don't
+// run hooks themselves.
+type hookContextKey struct{}
+
+func isHookContext(ctx context.Context) bool {
+	return ctx.Value(hookContextKey{}) != nil
+}
+
+// withAllHooks runs the before_all hooks that apply to the given calls, then
+// the given function and finally the after_all hooks.
+func (e *Executor) withAllHooks(ctx context.Context, calls []*Call, run func(ctx context.Context) error) error {
+	if len(e.Taskfile.Hooks) == 0 || isHookContext(ctx) {
+		return run(ctx)
+	}
+
+	names := make([]string, 0, len(calls))
+	for _, call := range calls {
+		names = append(names, call.Task)
+	}
+	var hooks []*ast.Hooks
+	for _, h := range e.Taskfile.Hooks {
+		if slices.ContainsFunc(names, h.AppliesTo) {
+			hooks = append(hooks, h)
+		}
+	}
+	if len(hooks) == 0 {
+		return run(ctx)
+	}
+
+	vars := ast.NewVars()
+	vars.Set("HOOK_TASKS", ast.Var{Value: names})
+	if err := e.runHooks(ctx, "before_all", hooks, vars); err != nil {
+		return err
+	}
+	err := run(ctx)
+	setHookResult(vars, err)
+	if hookErr := e.runHooks(ctx, "after_all", hooks, vars); hookErr != nil {
+		if err == nil {
+			return hookErr
+		}
+		e.Logger.VerboseErrf(logger.Yellow, "task: ignored error in after_all hook: %v\n", hookErr)
+	}
+	return err
+}
+
+// withEachHooks wraps the execution of the given task with the before_each,
+// on_failure and after_each hooks that apply to it.
+func (e *Executor) withEachHooks(t *ast.Task, run func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if len(e.Taskfile.Hooks) == 0 || isHookContext(ctx) {
+			return run(ctx)
+		}
+
+		var hooks []*ast.Hooks
+		for _, h := range e.Taskfile.Hooks {
+			if h.AppliesTo(t.Task) {
+				hooks = append(hooks, h)
+			}
+		}
+		if len(hooks) == 0 {
+			return run(ctx)
+		}
+
+		vars := ast.NewVars()
+		vars.Set("HOOK_TASK", ast.Var{Value: t.Task})
+		if err := e.runHooks(ctx, "before_each", hooks, vars); err != nil {
+			return err
+		}
+		err := run(ctx)
+		setHookResult(vars, err)
+		kinds := []string{"after_each"}
+		if err != nil {
+			kinds = []string{"on_failure", "after_each"}
+		}
+		for _, kind := range kinds {
+			if hookErr := e.runHooks(ctx, kind, hooks, vars); hookErr != nil {
+				if err == nil {
+					return hookErr
+				}
+				e.Logger.VerboseErrf(logger.Yellow, "task: ignored error in %s hook: %v\n", kind, hookErr)
+			}
+		}
+		return err
+	}
+}
+
+// setHookResult exposes the result of the tasks to the after hooks.
+func setHookResult(vars *ast.Vars, err error) {
+	exitCode := 0
+	message := ""
+	if err != nil {
+		exitCode = 1
+		var exitStatus interp.ExitStatus
+		if errors.As(err, &exitStatus) {
+			exitCode = int(exitStatus)
+		}
+		message = err.Error()
+	}
+	vars.Set("HOOK_EXIT_CODE", ast.Var{Value: strconv.Itoa(exitCode)})
+	vars.Set("HOOK_ERROR", ast.Var{Value: message})
+}
+
+// runHooks runs the commands of the given kind of hooks. The before hooks are
+// run from the outermost Taskfile to the innermost one, and the after hooks
+// the other way around.
+func (e *Executor) runHooks(ctx context.Context, kind string, hooks []*ast.Hooks, vars *ast.Vars) error {
+	hooks = slices.Clone(hooks)
+	if !strings.HasPrefix(kind, "before") {
+		slices.Reverse(hooks)
+	}
+	for _, h := range hooks {
+		var cmds []*ast.Cmd
+		switch kind {
+		case "before_all":
+			cmds = h.BeforeAll
+		case "after_all":
+			cmds = h.AfterAll
+		case "before_each":
+			cmds = h.BeforeEach
+		case "after_each":
+			cmds = h.AfterEach
+		case "on_failure":
+			cmds = h.OnFailure
+		}
+		for _, cmd := range cmds {
+			if err := e.runHook(ctx, kind, h, cmd, vars); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Executor) runHook(ctx context.Context, kind string, h *ast.Hooks, cmd *ast.Cmd, vars *ast.Vars) error {
+	if cmd == nil || !shouldRunOnCurrentPlatform(cmd.Platforms) {
+		return nil
+	}
+	name := kind
+	if h.Namespace != "" {
+		name = h.Namespace + ast.NamespaceSeparator + kind
+	}
+	ctx = context.WithValue(ctx, hookContextKey{}, true)
+
+	// The hooks of included Taskfiles get the vars of their include, like the
+	// tasks of these Taskfiles
+	hookVars, err := e.Compiler.GetVariables(&ast.Task{
+		Dir:                  h.Dir,
+		IncludeVars:          h.IncludeVars,
+		IncludedTaskfileVars: h.IncludedTaskfileVars,
+		Location:             &ast.Location{},
+	}, &Call{Vars: vars})
+	if err != nil {
+		return err
+	}
+	cache := &templater.Cache{Vars: hookVars}
+	dir := filepathext.SmartJoin(e.Dir, templater.Replace(h.Dir, cache))
+	envVars := ast.NewVars()
+	envVars.Merge(templater.ReplaceVars(e.Taskfile.Env, cache), nil)
+	envVars.Merge(templater.ReplaceVars(h.Env, cache), nil)
+	environ := env.GetFromVars(envVars)
+	condition := templater.Replace(cmd.If, cache)
+	task := templater.Replace(cmd.Task, cache)
+	command := templater.Replace(cmd.Cmd, cache)
+	callVars := templater.ReplaceVars(cmd.Vars, cache)
+	if err := cache.Err(); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(condition) != "" {
+		if err := execext.RunCommand(ctx, &execext.RunCommandOptions{
+			Command: condition,
+			Dir:     dir,
+			Env:     environ,
+		}); err != nil {
+			e.Logger.VerboseOutf(logger.Yellow, "task: [%s] if condition not met - skipped\n", name)
+			return nil
+		}
+	}
+
+	switch {
+	case task != "":
+		if callVars == nil {
+			callVars = ast.NewVars()
+		}
+		for k, v := range vars.All() {
+			if _, ok := callVars.Get(k); !ok {
+				callVars.Set(k, v)
+			}
+		}
+		// The hooks of each task run while the task holds a concurrency slot
+		if kind != "before_all" && kind != "after_all" {
+			reacquire := e.releaseConcurrencyLimit()
+			defer reacquire()
+		}
+
+		err = e.RunTask(ctx, &Call{Task: task, Vars: callVars, Silent: cmd.Silent, Indirect: true})
+	case command != "":
+		if e.Verbose || (!cmd.Silent && !e.Taskfile.Silent && !e.Silent) {
+			e.Logger.Errf(logger.Green, "task: [%s] %s\n", name, command)
+		}
+		if e.Dry {
+			return nil
+		}
+		err = execext.RunCommand(ctx, &execext.RunCommandOptions{
+			Command:   command,
+			Dir:       dir,
+			Env:       environ,
+			PosixOpts: slicesext.UniqueJoin(e.Taskfile.Set, cmd.Set),
+			BashOpts:  slicesext.UniqueJoin(e.Taskfile.Shopt, cmd.Shopt),
+			Sh:        effectiveSh(e.Taskfile.Sh, nil, cmd.Sh),
+			Stdin:     e.Stdin,
+			Stdout:    e.Stdout,
+			Stderr:    e.Stderr,
+		})
+	}
+	if err != nil && cmd.IgnoreError {
+		e.Logger.VerboseErrf(logger.Yellow, "task: [%s] hook error ignored: %v\n", name, err)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package buildcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// archive writes the given files to w as a gzipped tarball. Names are stored
+// relative to dir. Anything but regular files is skipped.
+func archive(w io.Writer, dir string, files []string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, f := range files {
+		info, err := os.Lstat(f)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		name, err := filepath.Rel(dir, f)
+		if err != nil || !filepath.IsLocal(name) {
+			return fmt.Errorf("buildcache: %q is not inside %q", f, dir)
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     filepath.ToSlash(name),
+			Mode:     int64(info.Mode().Perm()),
+			Size:     info.Size(),
+		}); err != nil {
+			return err
+		}
+		if err := copyFile(tw, f); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// extract writes the files of a gzipped tarball created by [archive] to dir,
+// replacing existing files.
+func extract(dir string, r io.Reader) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(header.Name)
+		if header.Typeflag != tar.TypeReg || !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid entry %q", header.Name)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		// Remove the file first so that its permissions are replaced as well
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(f, tr)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,84 @@
+// Package buildcache implements a content-addressed cache for the files
+// generated by tasks. Entries are gzipped tarballs stored under a key that
+// identifies the inputs of the task, so that the files can be restored instead
+// of being built again.
+package buildcache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotFound is returned by a [Store] when it has no entry for a key.
+var ErrNotFound = errors.New("buildcache: entry not found")
+
+// A Store saves and loads cache entries by key.
+type Store interface {
+	// Get returns the entry stored under the given key or [ErrNotFound].
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Put stores the entry read from r under the given key.
+	Put(ctx context.Context, key string, r io.Reader) error
+}
+
+// Cache restores and saves generated files using one or more stores. Stores
+// are queried in order, so faster ones, like a local directory, should come
+// first.
+type Cache struct {
+	stores []Store
+}
+
+// New creates a new [Cache] using the given stores.
+func New(stores ...Store) *Cache {
+	return &Cache{stores: stores}
+}
+
+// Restore extracts the entry stored under the given key into dir. It returns
+// false if none of the stores has an entry for the key. Entries found in a
+// store are also copied to the stores before it.
+func (c *Cache) Restore(ctx context.Context, key, dir string) (bool, error) {
+	for i, store := range c.stores {
+		data, err := get(ctx, store, key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if err := extract(dir, bytes.NewReader(data)); err != nil {
+			return false, fmt.Errorf("buildcache: unable to extract %q: %w", key, err)
+		}
+		for _, s := range c.stores[:i] {
+			if err := s.Put(ctx, key, bytes.NewReader(data)); err != nil {
+				return true, err
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// Save archives the given files and stores them under the given key in all
+// stores. Files must be inside dir and are restored relative to it.
+func (c *Cache) Save(ctx context.Context, key, dir string, files []string) error {
+	var buf bytes.Buffer
+	if err := archive(&buf, dir, files); err != nil {
+		return err
+	}
+	var errs []error
+	for _, store := range c.stores {
+		errs = append(errs, store.Put(ctx, key, bytes.NewReader(buf.Bytes())))
+	}
+	return errors.Join(errs...)
+}
+
+func get(ctx context.Context, store Store, key string) ([]byte, error) {
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,196 @@
+package buildcache_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/internal/buildcache"
+)
+
+const key = "0123456789abcdef"
+
+func newServer(t *testing.T) (*httptest.Server, map[string][]byte) {
+	t.Helper()
+	var mu sync.Mutex
+	entries := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := entries[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			entries[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, entries
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCacheDir(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cache := buildcache.New(buildcache.NewDirStore(t.TempDir()))
+
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "bin", "app"), "app")
+	writeFile(t, filepath.Join(src, "app.txt"), "txt")
+
+	restored, err := cache.Restore(ctx, key, t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, restored)
+
+	require.NoError(t, cache.Save(ctx, key, src, []string{
+		filepath.Join(src, "bin", "app"),
+		filepath.Join(src, "app.txt"),
+	}))
+
+	dst := t.TempDir()
+	writeFile(t, filepath.Join(dst, "app.txt"), "old")
+	restored, err = cache.Restore(ctx, key, dst)
+	require.NoError(t, err)
+	assert.True(t, restored)
+	assert.Equal(t, "app", readFile(t, filepath.Join(dst, "bin", "app")))
+	assert.Equal(t, "txt", readFile(t, filepath.Join(dst, "app.txt")))
+}
+
+func TestCacheHTTP(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server, entries := newServer(t)
+
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "out.txt"), "out")
+	remote := buildcache.New(buildcache.NewHTTPStore(server.URL+"/", ""))
+	require.NoError(t, remote.Save(ctx, key, src, []string{filepath.Join(src, "out.txt")}))
+	assert.Contains(t, entries, "/"+key)
+
+	// A fresh local store is filled from the remote one on a hit
+	localDir := t.TempDir()
+	cache := buildcache.New(buildcache.NewDirStore(localDir), buildcache.NewHTTPStore(server.URL, ""))
+	dst := t.TempDir()
+	restored, err := cache.Restore(ctx, key, dst)
+	require.NoError(t, err)
+	assert.True(t, restored)
+	assert.Equal(t, "out", readFile(t, filepath.Join(dst, "out.txt")))
+
+	delete(entries, "/"+key)
+	restored, err = buildcache.New(buildcache.NewDirStore(localDir)).Restore(ctx, key, t.TempDir())
+	require.NoError(t, err)
+	assert.True(t, restored)
+
+	restored, err = remote.Restore(ctx, "missing", t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
+
+func TestCacheHTTPError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	cache := buildcache.New(buildcache.NewHTTPStore(server.URL, ""))
+	_, err := cache.Restore(context.Background(), key, t.TempDir())
+	assert.ErrorContains(t, err, "500")
+}
+
+func TestCacheHTTPToken(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := buildcache.New(buildcache.NewHTTPStore(server.URL, "")).Restore(context.Background(), key, t.TempDir())
+	assert.ErrorContains(t, err, "401")
+	restored, err := buildcache.New(buildcache.NewHTTPStore(server.URL, "secret")).Restore(context.Background(), key, t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, restored)
+}
+
+type memStore map[string][]byte
+
+func (s memStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s[key]
+	if !ok {
+		return nil, buildcache.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s memStore) Put(_ context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	s[key] = data
+	return err
+}
+
+func TestCacheRejectsEntriesOutsideDir(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "out.txt"), "out")
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+	writeFile(t, outside, "outside")
+
+	cache := buildcache.New(memStore{})
+	err := cache.Save(ctx, key, src, []string{outside})
+	assert.ErrorContains(t, err, "is not inside")
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "../out.txt", Mode: 0o644, Size: 3}))
+	_, err = tw.Write([]byte("out"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	cache = buildcache.New(memStore{key: buf.Bytes()})
+	_, err = cache.Restore(ctx, key, t.TempDir())
+	assert.ErrorContains(t, err, `invalid entry "../out.txt"`)
+}
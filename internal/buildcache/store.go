@@ -0,0 +1,122 @@
+package buildcache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DirStore is a [Store] that keeps entries in a local directory.
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore creates a new [DirStore] in the given directory. The directory
+// is created when the first entry is stored.
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{dir: dir}
+}
+
+func (s *DirStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *DirStore) Put(_ context.Context, key string, r io.Reader) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that concurrent readers never see
+	// a partial entry
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *DirStore) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key+".tar.gz")
+}
+
+// HTTPStore is a [Store] that keeps entries on an HTTP server. Entries are
+// downloaded with GET and uploaded with PUT requests to <url>/<key>. A 404
+// response is treated as a cache miss. Credentials can be given as part of
+// the URL and are sent using basic authentication, or as a token sent in the
+// Authorization header.
+type HTTPStore struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// NewHTTPStore creates a new [HTTPStore] for the given base URL. The token, if
+// any, is sent as a bearer token with every request.
+func NewHTTPStore(url, token string) *HTTPStore {
+	return &HTTPStore{
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+		client: &http.Client{},
+	}
+}
+
+func (s *HTTPStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"/"+key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("buildcache: unexpected status %q downloading %q", resp.Status, key)
+	}
+}
+
+func (s *HTTPStore) Put(ctx context.Context, key string, r io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.url+"/"+key, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("buildcache: unexpected status %q uploading %q", resp.Status, key)
+	}
+	return nil
+}
+
+func (s *HTTPStore) do(req *http.Request) (*http.Response, error) {
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	return s.client.Do(req)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -31,6 +32,11 @@ type RunCommandOptions struct {
 	Stdin     io.Reader
 	Stdout    io.Writer
 	Stderr    io.Writer
+	// KillProcessTree makes sure that all processes started by the command
+	// are stopped when the context is cancelled, not only the direct child.
+	// Commands reading from a terminal are left in the foreground process
+	// group, so only their direct child is stopped.
+	KillProcessTree bool
 }
 
 // RunCommand runs a shell command
@@ -61,7 +67,7 @@ func RunCommand(ctx context.Context, opts *RunCommandOptions) error {
 	r, err := interp.New(
 		interp.Params(params...),
 		interp.Env(expand.ListEnviron(environ...)),
-		interp.ExecHandlers(execHandlers(opts.Sh)...),
+		interp.ExecHandlers(execHandlers(opts.Sh, opts.KillProcessTree)...),
 		interp.OpenHandler(openHandler),
 		interp.StdIO(opts.Stdin, opts.Stdout, opts.Stderr),
 		dirOption(opts.Dir),
@@ -126,6 +132,12 @@ func ExpandLiteral(s string) (string, error) {
 // variables. It also expands brace expressions ({a.b}) and globs (*/**) and
 // returns the results as a list of strings.
 func ExpandFields(s string) ([]string, error) {
+	return ExpandFieldsWithReadDir(s, os.ReadDir)
+}
+
+// ExpandFieldsWithReadDir works like [ExpandFields], but uses the given
+// function to list the entries of directories while expanding globs.
+func ExpandFieldsWithReadDir(s string, readDir func(string) ([]fs.DirEntry, error)) ([]string, error) {
 	s = escape(s)
 	p := syntax.NewParser()
 	var words []*syntax.Word
@@ -134,7 +146,7 @@ func ExpandFields(s string) ([]string, error) {
 	}
 	cfg := &expand.Config{
 		Env:      expand.FuncEnviron(os.Getenv),
-		ReadDir2: os.ReadDir,
+		ReadDir2: readDir,
 		GlobStar: true,
 		NullGlob: true,
 	}
@@ -190,13 +202,16 @@ func isJoinMode(sh []string) bool {
 	return sh[len(sh)-1] == flag
 }
 
-func execHandlers(sh []string) (handlers []func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc) {
+func execHandlers(sh []string, killTree bool) (handlers []func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc) {
 	if len(sh) > 0 {
-		handlers = append(handlers, customShHandler(sh))
+		handlers = append(handlers, customShHandler(sh, killTree))
 	}
 	if useGoCoreUtils {
 		handlers = append(handlers, coreutils.ExecHandler)
 	}
+	if killTree {
+		handlers = append(handlers, processTreeHandler)
+	}
 	return handlers
 }
 
@@ -220,7 +235,7 @@ func execHandlers(sh []string) (handlers []func(next interp.ExecHandlerFunc) int
 //     Note: POSIX single-quote escaping is compatible with POSIX shells but
 //     PowerShell uses different quoting rules; commands without embedded
 //     single quotes work fine with pwsh/powershell.
-func customShHandler(sh []string) func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+func customShHandler(sh []string, killTree bool) func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
 	joinMode := isJoinMode(sh)
 
 	return func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
@@ -229,13 +244,7 @@ func customShHandler(sh []string) func(next interp.ExecHandlerFunc) interp.ExecH
 
 			// Collect exported environment variables from the interpreter state.
 			// This captures any variables set/exported by the shell script so far.
-			var envList []string
-			hc.Env.Each(func(name string, vr expand.Variable) bool {
-				if vr.Exported && vr.Kind == expand.String {
-					envList = append(envList, name+"="+vr.Str)
-				}
-				return true
-			})
+			envList := exportedEnv(hc.Env)
 
 			// Build the argument list for the custom shell.
 			var extraArgs []string
@@ -253,6 +262,11 @@ func customShHandler(sh []string) func(next interp.ExecHandlerFunc) interp.ExecH
 			cmd.Stdin = hc.Stdin
 			cmd.Stdout = hc.Stdout
 			cmd.Stderr = hc.Stderr
+			if killTree && canUseProcessGroup(hc.Stdin) {
+				setProcessGroup(cmd)
+				cmd.Cancel = func() error { return killProcessTree(cmd) }
+				cmd.WaitDelay = killTimeout
+			}
 
 			if err := cmd.Run(); err != nil {
 				var exitErr *exec.ExitError
@@ -0,0 +1,105 @@
+package execext
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"golang.org/x/term"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+
+	"github.com/go-task/task/v3/errors"
+)
+
+// killTimeout is how long a process tree is given to exit after being
+// terminated before it is killed. This matches the default used by
+// [interp.DefaultExecHandler].
+const killTimeout = 2 * time.Second
+
+// processTreeHandler returns an exec handler middleware that starts each
+// command in its own process group so that, when the context is cancelled
+// (e.g. because a timeout expired), the whole process tree is stopped instead
+// of only the direct child. Without this, background jobs and grandchildren of
+// the command would keep running and hold on to its output.
+//
+// Commands reading from a terminal are passed on to the next handler, since a
+// process outside of the foreground process group of the terminal is stopped
+// as soon as it reads from it. Only their direct child is stopped on timeouts.
+func processTreeHandler(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+	return func(ctx context.Context, args []string) error {
+		hc := interp.HandlerCtx(ctx)
+		if !canUseProcessGroup(hc.Stdin) {
+			return next(ctx, args)
+		}
+		path, err := interp.LookPathDir(hc.Dir, hc.Env, args[0])
+		if err != nil {
+			fmt.Fprintln(hc.Stderr, err)
+			return interp.ExitStatus(127)
+		}
+
+		cmd := &exec.Cmd{
+			Path:      path,
+			Args:      args,
+			Env:       exportedEnv(hc.Env),
+			Dir:       hc.Dir,
+			Stdin:     hc.Stdin,
+			Stdout:    hc.Stdout,
+			Stderr:    hc.Stderr,
+			WaitDelay: killTimeout,
+		}
+		setProcessGroup(cmd)
+
+		if err := cmd.Start(); err != nil {
+			fmt.Fprintf(hc.Stderr, "%v\n", err)
+			return interp.ExitStatus(127)
+		}
+		defer forwardSignals(cmd)()
+
+		exited := make(chan struct{})
+		defer close(exited)
+		stop := context.AfterFunc(ctx, func() {
+			_ = terminateProcessTree(cmd)
+			select {
+			case <-exited:
+			case <-time.After(killTimeout):
+			}
+			_ = killProcessTree(cmd)
+		})
+		defer stop()
+
+		err = cmd.Wait()
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return interp.ExitStatus(uint8(exitErr.ExitCode()))
+		}
+		return err
+	}
+}
+
+// canUseProcessGroup returns whether a command reading from the given stdin
+// can be started in its own process group, which is the case unless it reads
+// from a terminal.
+func canUseProcessGroup(stdin io.Reader) bool {
+	f, ok := stdin.(*os.File)
+	return !ok || !term.IsTerminal(int(f.Fd()))
+}
+
+// exportedEnv returns the exported variables of the interpreter state in the
+// "key=value" form expected by [exec.Cmd].
+func exportedEnv(env expand.Environ) []string {
+	var list []string
+	env.Each(func(name string, vr expand.Variable) bool {
+		if vr.Exported && vr.Kind == expand.String {
+			list = append(list, name+"="+vr.Str)
+		}
+		return true
+	})
+	return list
+}
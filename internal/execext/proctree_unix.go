@@ -0,0 +1,40 @@
+//go:build !windows
+
+package execext
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func terminateProcessTree(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+func killProcessTree(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// forwardSignals relays interrupt signals received by Task to the process
+// group of the command. This is needed because a process that is not in the
+// foreground process group does not receive signals sent by the terminal (e.g.
+// Ctrl+C).
+func forwardSignals(cmd *exec.Cmd) func() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range ch {
+			_ = syscall.Kill(-cmd.Process.Pid, sig.(syscall.Signal))
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package execext
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// NOTE: Windows has no process groups that can be signalled like on Unix, so
+// the command stays attached to the console and receives Ctrl+C directly.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func terminateProcessTree(cmd *exec.Cmd) error {
+	return killProcessTree(cmd)
+}
+
+func killProcessTree(cmd *exec.Cmd) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
+
+func forwardSignals(cmd *exec.Cmd) func() {
+	return func() {}
+}
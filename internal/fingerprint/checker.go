@@ -7,14 +7,17 @@ import (
 )
 
 // StatusCheckable defines any type that can check if the status of a task is up-to-date.
+// When the task is not up-to-date, a [Reason] describing why should be returned.
 type StatusCheckable interface {
-	IsUpToDate(ctx context.Context, t *ast.Task) (bool, error)
+	IsUpToDate(ctx context.Context, t *ast.Task) (bool, *Reason, error)
 }
 
 // SourcesCheckable defines any type that can check if the sources of a task are up-to-date.
+// When the task is not up-to-date, a [Reason] describing why should be returned.
 type SourcesCheckable interface {
-	IsUpToDate(t *ast.Task) (bool, error)
+	IsUpToDate(t *ast.Task) (bool, *Reason, error)
 	Value(t *ast.Task) (any, error)
 	OnError(t *ast.Task) error
+	OnSuccess(t *ast.Task) error
 	Kind() string
 }
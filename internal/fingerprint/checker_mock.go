@@ -39,7 +39,7 @@ func (_m *MockStatusCheckable) EXPECT() *MockStatusCheckable_Expecter {
 }
 
 // IsUpToDate provides a mock function for the type MockStatusCheckable
-func (_mock *MockStatusCheckable) IsUpToDate(ctx context.Context, t *ast.Task) (bool, error) {
+func (_mock *MockStatusCheckable) IsUpToDate(ctx context.Context, t *ast.Task) (bool, *Reason, error) {
 	ret := _mock.Called(ctx, t)
 
 	if len(ret) == 0 {
@@ -47,8 +47,9 @@ func (_mock *MockStatusCheckable) IsUpToDate(ctx context.Context, t *ast.Task) (
 	}
 
 	var r0 bool
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *ast.Task) (bool, error)); ok {
+	var r1 *Reason
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ast.Task) (bool, *Reason, error)); ok {
 		return returnFunc(ctx, t)
 	}
 	if returnFunc, ok := ret.Get(0).(func(context.Context, *ast.Task) bool); ok {
@@ -56,12 +57,19 @@ func (_mock *MockStatusCheckable) IsUpToDate(ctx context.Context, t *ast.Task) (
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, *ast.Task) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *ast.Task) *Reason); ok {
 		r1 = returnFunc(ctx, t)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*Reason)
+		}
 	}
-	return r0, r1
+	if returnFunc, ok := ret.Get(2).(func(context.Context, *ast.Task) error); ok {
+		r2 = returnFunc(ctx, t)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
 // MockStatusCheckable_IsUpToDate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsUpToDate'
@@ -83,12 +91,12 @@ func (_c *MockStatusCheckable_IsUpToDate_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockStatusCheckable_IsUpToDate_Call) Return(b bool, err error) *MockStatusCheckable_IsUpToDate_Call {
-	_c.Call.Return(b, err)
+func (_c *MockStatusCheckable_IsUpToDate_Call) Return(b bool, reason *Reason, err error) *MockStatusCheckable_IsUpToDate_Call {
+	_c.Call.Return(b, reason, err)
 	return _c
 }
 
-func (_c *MockStatusCheckable_IsUpToDate_Call) RunAndReturn(run func(ctx context.Context, t *ast.Task) (bool, error)) *MockStatusCheckable_IsUpToDate_Call {
+func (_c *MockStatusCheckable_IsUpToDate_Call) RunAndReturn(run func(ctx context.Context, t *ast.Task) (bool, *Reason, error)) *MockStatusCheckable_IsUpToDate_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -121,7 +129,7 @@ func (_m *MockSourcesCheckable) EXPECT() *MockSourcesCheckable_Expecter {
 }
 
 // IsUpToDate provides a mock function for the type MockSourcesCheckable
-func (_mock *MockSourcesCheckable) IsUpToDate(t *ast.Task) (bool, error) {
+func (_mock *MockSourcesCheckable) IsUpToDate(t *ast.Task) (bool, *Reason, error) {
 	ret := _mock.Called(t)
 
 	if len(ret) == 0 {
@@ -129,8 +137,9 @@ func (_mock *MockSourcesCheckable) IsUpToDate(t *ast.Task) (bool, error) {
 	}
 
 	var r0 bool
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(*ast.Task) (bool, error)); ok {
+	var r1 *Reason
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(*ast.Task) (bool, *Reason, error)); ok {
 		return returnFunc(t)
 	}
 	if returnFunc, ok := ret.Get(0).(func(*ast.Task) bool); ok {
@@ -138,12 +147,19 @@ func (_mock *MockSourcesCheckable) IsUpToDate(t *ast.Task) (bool, error) {
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
-	if returnFunc, ok := ret.Get(1).(func(*ast.Task) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(*ast.Task) *Reason); ok {
 		r1 = returnFunc(t)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*Reason)
+		}
 	}
-	return r0, r1
+	if returnFunc, ok := ret.Get(2).(func(*ast.Task) error); ok {
+		r2 = returnFunc(t)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
 }
 
 // MockSourcesCheckable_IsUpToDate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsUpToDate'
@@ -164,12 +180,12 @@ func (_c *MockSourcesCheckable_IsUpToDate_Call) Run(run func(t *ast.Task)) *Mock
 	return _c
 }
 
-func (_c *MockSourcesCheckable_IsUpToDate_Call) Return(b bool, err error) *MockSourcesCheckable_IsUpToDate_Call {
-	_c.Call.Return(b, err)
+func (_c *MockSourcesCheckable_IsUpToDate_Call) Return(b bool, reason *Reason, err error) *MockSourcesCheckable_IsUpToDate_Call {
+	_c.Call.Return(b, reason, err)
 	return _c
 }
 
-func (_c *MockSourcesCheckable_IsUpToDate_Call) RunAndReturn(run func(t *ast.Task) (bool, error)) *MockSourcesCheckable_IsUpToDate_Call {
+func (_c *MockSourcesCheckable_IsUpToDate_Call) RunAndReturn(run func(t *ast.Task) (bool, *Reason, error)) *MockSourcesCheckable_IsUpToDate_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -263,6 +279,51 @@ func (_c *MockSourcesCheckable_OnError_Call) RunAndReturn(run func(t *ast.Task)
 	return _c
 }
 
+// OnSuccess provides a mock function for the type MockSourcesCheckable
+func (_mock *MockSourcesCheckable) OnSuccess(t *ast.Task) error {
+	ret := _mock.Called(t)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OnSuccess")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(*ast.Task) error); ok {
+		r0 = returnFunc(t)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSourcesCheckable_OnSuccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OnSuccess'
+type MockSourcesCheckable_OnSuccess_Call struct {
+	*mock.Call
+}
+
+// OnSuccess is a helper method to define mock.On call
+//   - t
+func (_e *MockSourcesCheckable_Expecter) OnSuccess(t interface{}) *MockSourcesCheckable_OnSuccess_Call {
+	return &MockSourcesCheckable_OnSuccess_Call{Call: _e.mock.On("OnSuccess", t)}
+}
+
+func (_c *MockSourcesCheckable_OnSuccess_Call) Run(run func(t *ast.Task)) *MockSourcesCheckable_OnSuccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*ast.Task))
+	})
+	return _c
+}
+
+func (_c *MockSourcesCheckable_OnSuccess_Call) Return(err error) *MockSourcesCheckable_OnSuccess_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSourcesCheckable_OnSuccess_Call) RunAndReturn(run func(t *ast.Task) error) *MockSourcesCheckable_OnSuccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Value provides a mock function for the type MockSourcesCheckable
 func (_mock *MockSourcesCheckable) Value(t *ast.Task) (any, error) {
 	ret := _mock.Called(t)
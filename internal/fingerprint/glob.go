@@ -1,18 +1,39 @@
 package fingerprint
 
 import (
+	"io/fs"
 	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
 
+	"mvdan.cc/sh/v3/pattern"
+
 	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/ignore"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
+// Sources returns the files matching the sources of the task. Unless the task
+// disabled it, the entries ignored by .gitignore and .taskignore files are
+// skipped while expanding wildcards, so ignored directories are not walked.
+// Paths given without wildcards are always included.
+func Sources(t *ast.Task) ([]string, error) {
+	if !t.UsesIgnoreFiles() {
+		return Globs(t.Dir, t.Sources)
+	}
+	return globsWithReadDir(t.Dir, t.Sources, ignore.New(t.Dir).ReadDir)
+}
+
 func Globs(dir string, globs []*ast.Glob) ([]string, error) {
+	return globsWithReadDir(dir, globs, os.ReadDir)
+}
+
+func globsWithReadDir(dir string, globs []*ast.Glob, readDir func(string) ([]fs.DirEntry, error)) ([]string, error) {
 	resultMap := make(map[string]bool)
 	for _, g := range globs {
-		matches, err := glob(dir, g.Glob)
+		matches, err := globWithReadDir(dir, g.Glob, readDir)
 		if err != nil {
 			continue
 		}
@@ -23,17 +44,57 @@ func Globs(dir string, globs []*ast.Glob) ([]string, error) {
 	return collectKeys(resultMap), nil
 }
 
+// Matcher returns a function telling whether an absolute path matches the
+// globs, the later ones taking precedence like in [Globs]. Unlike [Globs], the
+// paths don't need to exist, so it can be used for deleted files.
+func Matcher(dir string, globs []*ast.Glob) func(path string) bool {
+	type matcher struct {
+		re     *regexp.Regexp
+		negate bool
+	}
+	matchers := make([]matcher, 0, len(globs))
+	for _, g := range globs {
+		expanded, err := execext.ExpandLiteral(filepath.ToSlash(filepathext.SmartJoin(dir, g.Glob)))
+		if err != nil {
+			continue
+		}
+		expr, err := pattern.Regexp(expanded, pattern.Filenames|pattern.EntireString)
+		if err != nil {
+			continue
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			continue
+		}
+		matchers = append(matchers, matcher{re: re, negate: g.Negate})
+	}
+	return func(path string) bool {
+		path = filepath.ToSlash(path)
+		matched := false
+		for _, m := range matchers {
+			if m.re.MatchString(path) {
+				matched = !m.negate
+			}
+		}
+		return matched
+	}
+}
+
 func glob(dir string, g string) ([]string, error) {
+	return globWithReadDir(dir, g, os.ReadDir)
+}
+
+func globWithReadDir(dir string, g string, readDir func(string) ([]fs.DirEntry, error)) ([]string, error) {
 	g = filepathext.SmartJoin(dir, g)
 
-	fs, err := execext.ExpandFields(g)
+	files, err := execext.ExpandFieldsWithReadDir(g, readDir)
 	if err != nil {
 		return nil, err
 	}
 
-	results := make(map[string]bool, len(fs))
+	results := make(map[string]bool, len(files))
 
-	for _, f := range fs {
+	for _, f := range files {
 		info, err := os.Stat(f)
 		if err != nil {
 			return nil, err
@@ -56,3 +117,20 @@ func collectKeys(m map[string]bool) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+// relPath returns the given path relative to dir, using forward slashes.
+// Paths that can't be made relative are returned unchanged.
+func relPath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		path = rel
+	}
+	return filepath.ToSlash(path)
+}
+
+func relPaths(dir string, paths []string) []string {
+	rel := make([]string, 0, len(paths))
+	for _, p := range paths {
+		rel = append(rel, relPath(dir, p))
+	}
+	return rel
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package fingerprint
+
+import (
+	"os"
+	"syscall"
+)
+
+// inode returns the inode number of the file, so that replacing a file by
+// another one with the same size and modification time is noticed.
+func inode(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(stat.Ino) //nolint:unconvert // not uint64 on every platform
+	}
+	return 0
+}
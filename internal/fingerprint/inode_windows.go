@@ -0,0 +1,11 @@
+//go:build windows
+
+package fingerprint
+
+import "os"
+
+// NOTE: This always returns 0 since the file index is not part of the
+// information returned by os.Stat on Windows.
+func inode(info os.FileInfo) uint64 {
+	return 0
+}
@@ -0,0 +1,81 @@
+package fingerprint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReasonKind identifies why a task is not up-to-date.
+type ReasonKind string
+
+const (
+	// ReasonNoSourcesOrStatus is used for tasks without sources or status,
+	// which are never considered up-to-date.
+	ReasonNoSourcesOrStatus ReasonKind = "no-sources-or-status"
+	// ReasonMethodNone is used when the "none" method is used, which never
+	// considers sources up-to-date.
+	ReasonMethodNone ReasonKind = "method-none"
+	// ReasonStatusFailed is used when a status command exited non-zero.
+	ReasonStatusFailed ReasonKind = "status-failed"
+	// ReasonNoFingerprint is used when there is no fingerprint of a previous
+	// run to compare the sources with.
+	ReasonNoFingerprint ReasonKind = "no-fingerprint"
+	// ReasonSourcesChanged is used when source files were changed, added or
+	// removed since the last run.
+	ReasonSourcesChanged ReasonKind = "sources-changed"
+	// ReasonGeneratesMissing is used when a generates glob has no matches.
+	ReasonGeneratesMissing ReasonKind = "generates-missing"
+	// ReasonGeneratesChanged is used when generated files were changed, added
+	// or removed since the last successful run.
+	ReasonGeneratesChanged ReasonKind = "generates-changed"
+	// ReasonSourcesError is used when the sources could not be read.
+	ReasonSourcesError ReasonKind = "sources-error"
+)
+
+// Reason describes why a task is not up-to-date. Paths are relative to the
+// directory of the task.
+type Reason struct {
+	Kind ReasonKind `json:"kind"`
+	// Detail holds the failed status command, the missing generates glob or
+	// the error message, depending on the kind.
+	Detail  string   `json:"detail,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+func (r *Reason) String() string {
+	switch r.Kind {
+	case ReasonNoSourcesOrStatus:
+		return "no sources or status"
+	case ReasonMethodNone:
+		return `method is "none"`
+	case ReasonStatusFailed:
+		return fmt.Sprintf("status command %q failed", r.Detail)
+	case ReasonNoFingerprint:
+		return "no fingerprint of a previous run"
+	case ReasonGeneratesMissing:
+		return fmt.Sprintf("generates %q has no matching files", r.Detail)
+	case ReasonSourcesError:
+		return fmt.Sprintf("unable to read sources: %s", r.Detail)
+	}
+
+	what := "sources changed"
+	if r.Kind == ReasonGeneratesChanged {
+		what = "generated files changed"
+	}
+	var files []string
+	if len(r.Changed) > 0 {
+		files = append(files, "changed: "+strings.Join(r.Changed, ", "))
+	}
+	if len(r.Added) > 0 {
+		files = append(files, "added: "+strings.Join(r.Added, ", "))
+	}
+	if len(r.Removed) > 0 {
+		files = append(files, "removed: "+strings.Join(r.Removed, ", "))
+	}
+	if len(files) == 0 {
+		return what
+	}
+	return fmt.Sprintf("%s (%s)", what, strings.Join(files, "; "))
+}
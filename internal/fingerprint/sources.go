@@ -8,6 +8,8 @@ func NewSourcesChecker(method, tempDir string, dry bool) (SourcesCheckable, erro
 		return NewTimestampChecker(tempDir, dry), nil
 	case "checksum":
 		return NewChecksumChecker(tempDir, dry), nil
+	case "git":
+		return NewGitChecker(tempDir, dry), nil
 	case "none":
 		return NoneChecker{}, nil
 	default:
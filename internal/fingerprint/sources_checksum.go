@@ -3,12 +3,17 @@ package fingerprint
 import (
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/zeebo/xxh3"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/go-task/task/v3/internal/filepathext"
 	"github.com/go-task/task/v3/taskfile/ast"
@@ -19,6 +24,7 @@ import (
 type ChecksumChecker struct {
 	tempDir string
 	dry     bool
+	git     bool
 }
 
 func NewChecksumChecker(tempDir string, dry bool) *ChecksumChecker {
@@ -28,27 +34,37 @@ func NewChecksumChecker(tempDir string, dry bool) *ChecksumChecker {
 	}
 }
 
-func (checker *ChecksumChecker) IsUpToDate(t *ast.Task) (bool, error) {
+func (checker *ChecksumChecker) IsUpToDate(t *ast.Task) (bool, *Reason, error) {
 	if len(t.Sources) == 0 {
-		return false, nil
+		return false, &Reason{Kind: ReasonNoSourcesOrStatus}, nil
 	}
 
 	checksumFile := checker.checksumFilePath(t)
 
 	data, _ := os.ReadFile(checksumFile)
-	oldHash := strings.TrimSpace(string(data))
+	oldSums := parseChecksums(string(data))
 
-	newHash, err := checker.checksum(t)
+	newSums, err := checker.checksums(t)
 	if err != nil {
-		return false, nil
+		return false, &Reason{Kind: ReasonSourcesError, Detail: err.Error()}, nil
 	}
 
-	if !checker.dry && oldHash != newHash {
+	if !checker.dry && string(data) != newSums.String() {
 		_ = os.MkdirAll(filepathext.SmartJoin(checker.tempDir, "checksum"), 0o755)
-		if err = os.WriteFile(checksumFile, []byte(newHash+"\n"), 0o644); err != nil {
-			return false, err
+		if err = os.WriteFile(checksumFile, []byte(newSums.String()), 0o644); err != nil {
+			return false, nil, err
 		}
 	}
+	if !checker.dry && variant(t) != "" {
+		checker.pruneVariants(t)
+	}
+
+	if oldSums.hash == "" {
+		return false, &Reason{Kind: ReasonNoFingerprint}, nil
+	}
+	if oldSums.hash != newSums.hash {
+		return false, oldSums.diff(newSums), nil
+	}
 
 	if len(t.Generates) > 0 {
 		// For each specified 'generates' field, check whether the files actually exist
@@ -58,64 +74,397 @@ func (checker *ChecksumChecker) IsUpToDate(t *ast.Task) (bool, error) {
 			}
 			generates, err := glob(t.Dir, g.Glob)
 			if os.IsNotExist(err) {
-				return false, nil
+				return false, &Reason{Kind: ReasonGeneratesMissing, Detail: g.Glob}, nil
 			}
 			if err != nil {
-				return false, err
+				return false, nil, err
 			}
 			if len(generates) == 0 {
-				return false, nil
+				return false, &Reason{Kind: ReasonGeneratesMissing, Detail: g.Glob}, nil
 			}
 		}
 	}
 
-	return oldHash == newHash, nil
+	if t.Fingerprint != nil && t.Fingerprint.Generates {
+		return checker.checkGenerates(t)
+	}
+
+	return true, nil, nil
+}
+
+// checkGenerates compares the files generated by the task with the checksums
+// recorded after its last successful run.
+func (checker *ChecksumChecker) checkGenerates(t *ast.Task) (bool, *Reason, error) {
+	data, err := os.ReadFile(checker.generatesFilePath(t))
+	if os.IsNotExist(err) {
+		return false, &Reason{Kind: ReasonNoFingerprint}, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	oldSums := parseChecksums(string(data))
+
+	newSums, err := checker.generatesChecksums(t)
+	if err != nil {
+		return false, &Reason{Kind: ReasonSourcesError, Detail: err.Error()}, nil
+	}
+	if maps.Equal(oldSums.files, newSums.files) {
+		return true, nil, nil
+	}
+	reason := oldSums.diff(newSums)
+	reason.Kind = ReasonGeneratesChanged
+	return false, reason, nil
 }
 
 func (checker *ChecksumChecker) Value(t *ast.Task) (any, error) {
-	return checker.checksum(t)
+	sums, err := checker.checksums(t)
+	if err != nil {
+		return "", err
+	}
+	return sums.hash, nil
+}
+
+// SourceChecksums returns the checksum of each source of the task, keyed by its
+// path relative to the task directory.
+func (checker *ChecksumChecker) SourceChecksums(t *ast.Task) (map[string]string, error) {
+	sums, err := checker.checksums(t)
+	if err != nil {
+		return nil, err
+	}
+	return sums.files, nil
 }
 
 func (checker *ChecksumChecker) OnError(t *ast.Task) error {
 	if len(t.Sources) == 0 {
 		return nil
 	}
+	if err := os.Remove(checker.generatesFilePath(t)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
 	return os.Remove(checker.checksumFilePath(t))
 }
 
+// OnSuccess records the checksums of the files generated by the task, if its
+// fingerprint includes them.
+func (checker *ChecksumChecker) OnSuccess(t *ast.Task) error {
+	if checker.dry || len(t.Sources) == 0 || t.Fingerprint == nil || !t.Fingerprint.Generates {
+		return nil
+	}
+	sums, err := checker.generatesChecksums(t)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepathext.SmartJoin(checker.tempDir, "checksum"), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(checker.generatesFilePath(t), []byte(sums.String()), 0o644)
+}
+
 func (*ChecksumChecker) Kind() string {
 	return "checksum"
 }
 
-func (c *ChecksumChecker) checksum(t *ast.Task) (string, error) {
-	sources, err := Globs(t.Dir, t.Sources)
+// checksums holds the checksum of all the sources of a task, as well as the
+// checksum of each individual source file, keyed by its path relative to the
+// task directory.
+type checksums struct {
+	hash  string
+	files map[string]string
+}
+
+func (c *ChecksumChecker) checksums(t *ast.Task) (*checksums, error) {
+	sources, err := Sources(t)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	// The selected variables, environment variables and commands are part of
+	// the checksum as well, so that they are also available in CHECKSUM
+	if c.git {
+		if sums, ok, err := gitChecksums(t.Dir, sources, variant(t)); ok {
+			return sums, err
+		}
+	}
+	cache := loadStatCache(c.statCacheFilePath(t))
+	sums, err := sumFiles(t.Dir, sources, variant(t), cache)
+	if err != nil {
+		return nil, err
+	}
+	if !c.dry {
+		// The cache only saves time, so failing to write it is not an error
+		_ = cache.save()
+	}
+	return sums, nil
+}
+
+// generatesChecksums returns the checksums of the files generated by the task.
+func (c *ChecksumChecker) generatesChecksums(t *ast.Task) (*checksums, error) {
+	generates, err := Globs(t.Dir, t.Generates)
+	if err != nil {
+		return nil, err
+	}
+	return sumFiles(t.Dir, generates, "", nil)
+}
+
+// sumFiles returns the checksum of the given files, as well as the checksum of
+// each individual file. The extra string, if any, is added to the former. The
+// files are read concurrently, and not read at all if none of them changed
+// since their checksums were stored in the stat cache. The cache can be nil.
+func sumFiles(dir string, files []string, extra string, cache *statCache) (*checksums, error) {
+	infos := make([]os.FileInfo, len(files))
+	fileSums := make([]string, len(files))
+	var g errgroup.Group
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for i, f := range files {
+		g.Go(func() error {
+			info, err := os.Stat(f)
+			if err != nil {
+				return err
+			}
+			infos[i] = info
+			fileSums[i], _ = cache.get(f, info)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	key := listKey(files, extra)
+	hash, ok := "", !slices.Contains(fileSums, "")
+	if ok {
+		hash, ok = cache.getSum(key)
+	}
+	if !ok {
+		var err error
+		if hash, err = hashFiles(files, infos, fileSums, extra); err != nil {
+			return nil, err
+		}
+		for i, f := range files {
+			cache.set(f, infos[i], fileSums[i])
+		}
+		cache.setSum(key, hash, files)
+	}
+
+	sums := &checksums{hash: hash, files: make(map[string]string, len(files))}
+	for i, f := range files {
+		sums.files[relPath(dir, f)] = fileSums[i]
 	}
+	return sums, nil
+}
+
+// maxPrefetchSize is the size of the largest files read ahead by [hashFiles].
+// Larger files are only read when they are hashed, to bound memory usage.
+const maxPrefetchSize = 1 << 20
+
+// hashFiles returns the checksum of the names and contents of the given files,
+// in order, followed by the extra string, if any. The files are read ahead
+// concurrently, a few at a time. The checksum of each file is stored in sums,
+// unless it is already set.
+func hashFiles(files []string, infos []os.FileInfo, sums []string, extra string) (string, error) {
+	type content struct {
+		data []byte
+		err  error
+		// large is true when the file is too large to be read ahead
+		large bool
+	}
+	contents := make([]chan content, len(files))
+	for i := range contents {
+		contents[i] = make(chan content, 1)
+	}
+	limit := make(chan struct{}, runtime.GOMAXPROCS(0))
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for i, f := range files {
+			if infos[i].Size() > maxPrefetchSize {
+				contents[i] <- content{large: true}
+				continue
+			}
+			select {
+			case limit <- struct{}{}:
+			case <-done:
+				return
+			}
+			go func() {
+				data, err := os.ReadFile(f)
+				contents[i] <- content{data: data, err: err}
+			}()
+		}
+	}()
 
 	h := xxh3.New()
-	buf := make([]byte, 128*1024)
-	for _, f := range sources {
+	fh := xxh3.New()
+	var buf []byte
+	for i, f := range files {
+		c := <-contents[i]
+		if c.err != nil {
+			return "", c.err
+		}
 		// also sum the filename, so checksum changes for renaming a file
-		if _, err := io.CopyBuffer(h, strings.NewReader(filepath.Base(f)), buf); err != nil {
+		if _, err := io.WriteString(h, filepath.Base(f)); err != nil {
 			return "", err
 		}
-		f, err := os.Open(f)
-		if err != nil {
-			return "", err
+		fh.Reset()
+		if c.large {
+			if buf == nil {
+				buf = make([]byte, 128*1024)
+			}
+			if err := copyFile(io.MultiWriter(h, fh), f, buf); err != nil {
+				return "", err
+			}
+		} else {
+			<-limit
+			if _, err := io.MultiWriter(h, fh).Write(c.data); err != nil {
+				return "", err
+			}
 		}
-		if _, err = io.CopyBuffer(h, f, buf); err != nil {
+		if sums[i] == "" {
+			sums[i] = fmt.Sprintf("%x", fh.Sum64())
+		}
+	}
+
+	if extra != "" {
+		if _, err := io.WriteString(h, "\x00"+extra); err != nil {
 			return "", err
 		}
-		f.Close()
 	}
 
 	hash := h.Sum128()
 	return fmt.Sprintf("%x%x", hash.Hi, hash.Lo), nil
 }
 
+func copyFile(w io.Writer, path string, buf []byte) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.CopyBuffer(w, f, buf)
+	return err
+}
+
+// listKey identifies a list of files and the extra string added to their
+// checksum in the stat cache.
+func listKey(files []string, extra string) string {
+	h := xxh3.New()
+	for _, f := range files {
+		_, _ = io.WriteString(h, f+"\x00")
+	}
+	_, _ = io.WriteString(h, "\x00"+extra)
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// String returns the content of the checksum file: the checksum of all the
+// sources on the first line, followed by one line per source file in the
+// "<checksum>  <path>" format.
+func (sums *checksums) String() string {
+	var b strings.Builder
+	b.WriteString(sums.hash + "\n")
+	for _, path := range slices.Sorted(maps.Keys(sums.files)) {
+		fmt.Fprintf(&b, "%s  %s\n", sums.files[path], path)
+	}
+	return b.String()
+}
+
+// parseChecksums parses the content of a checksum file. Files written by older
+// versions of Task only contain the checksum of all the sources.
+func parseChecksums(data string) *checksums {
+	sums := &checksums{files: map[string]string{}}
+	lines := strings.Split(strings.TrimSpace(data), "\n")
+	sums.hash = strings.TrimSpace(lines[0])
+	for _, line := range lines[1:] {
+		if hash, path, ok := strings.Cut(line, "  "); ok {
+			sums.files[path] = hash
+		}
+	}
+	return sums
+}
+
+// diff returns a reason listing the source files that were changed, added or
+// removed compared to the given checksums.
+func (sums *checksums) diff(other *checksums) *Reason {
+	reason := &Reason{Kind: ReasonSourcesChanged}
+	if len(sums.files) == 0 {
+		return reason
+	}
+	for _, path := range slices.Sorted(maps.Keys(other.files)) {
+		oldHash, ok := sums.files[path]
+		switch {
+		case !ok:
+			reason.Added = append(reason.Added, path)
+		case oldHash != other.files[path]:
+			reason.Changed = append(reason.Changed, path)
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(sums.files)) {
+		if _, ok := other.files[path]; !ok {
+			reason.Removed = append(reason.Removed, path)
+		}
+	}
+	return reason
+}
+
+// checksumFilePath returns the path of the checksum file of the given task.
+// Each variant of the task, as selected by its fingerprint, gets its own file.
 func (checker *ChecksumChecker) checksumFilePath(t *ast.Task) string {
-	return filepath.Join(checker.tempDir, "checksum", normalizeFilename(t.Name()))
+	name := normalizeFilename(t.Name())
+	if v := variant(t); v != "" {
+		name += "@" + v
+	}
+	return filepath.Join(checker.tempDir, "checksum", name)
+}
+
+// maxVariants is the number of variants of a task whose checksums are kept.
+const maxVariants = 16
+
+// pruneVariants marks the current variant of the task as the most recently
+// used one, and removes the checksums of the least recently used variants
+// beyond maxVariants, so that variables with ever changing values, like
+// versions, don't fill the temporary directory.
+func (checker *ChecksumChecker) pruneVariants(t *ast.Task) {
+	now := time.Now()
+	_ = os.Chtimes(checker.checksumFilePath(t), now, now)
+
+	dir := filepath.Join(checker.tempDir, "checksum")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	prefix := normalizeFilename(t.Name()) + "@"
+	var variants []os.FileInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || strings.HasSuffix(name, ".generates") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			variants = append(variants, info)
+		}
+	}
+	if len(variants) <= maxVariants {
+		return
+	}
+	slices.SortFunc(variants, func(a, b os.FileInfo) int {
+		return b.ModTime().Compare(a.ModTime())
+	})
+	for _, info := range variants[maxVariants:] {
+		path := filepath.Join(dir, info.Name())
+		_ = os.Remove(path)
+		_ = os.Remove(path + ".generates")
+	}
+}
+
+// statCacheFilePath returns the path of the stat cache of the given task. It is
+// shared by all the variants of the task, as it only depends on the sources.
+func (checker *ChecksumChecker) statCacheFilePath(t *ast.Task) string {
+	return filepath.Join(checker.tempDir, "checksum", normalizeFilename(t.Name())+".stats")
+}
+
+// generatesFilePath returns the path of the file holding the checksums of the
+// files generated by the task. The suffix can't clash with the checksum file
+// of another task, as dots are replaced in task names.
+func (checker *ChecksumChecker) generatesFilePath(t *ast.Task) string {
+	return checker.checksumFilePath(t) + ".generates"
 }
 
 var checksumFilenameRegexp = regexp.MustCompile("[^A-z0-9]")
@@ -1,9 +1,14 @@
 package fingerprint
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/taskfile/ast"
 )
 
 func TestNormalizeFilename(t *testing.T) {
@@ -21,3 +26,76 @@ func TestNormalizeFilename(t *testing.T) {
 		assert.Equal(t, test.Out, normalizeFilename(test.In))
 	}
 }
+
+func TestChecksumsDiff(t *testing.T) {
+	t.Parallel()
+
+	old := &checksums{
+		hash: "abc",
+		files: map[string]string{
+			"a.txt":     "1",
+			"b.txt":     "2",
+			"dir/c.txt": "3",
+		},
+	}
+	parsed := parseChecksums(old.String())
+	assert.Equal(t, old, parsed)
+
+	current := &checksums{
+		hash: "def",
+		files: map[string]string{
+			"a.txt":     "1",
+			"b.txt":     "changed",
+			"dir/d.txt": "4",
+		},
+	}
+	assert.Equal(t, &Reason{
+		Kind:    ReasonSourcesChanged,
+		Changed: []string{"b.txt"},
+		Added:   []string{"dir/d.txt"},
+		Removed: []string{"dir/c.txt"},
+	}, parsed.diff(current))
+
+	// Checksum files written by older versions only contain the hash
+	legacy := parseChecksums("abc\n")
+	assert.Equal(t, "abc", legacy.hash)
+	assert.Equal(t, &Reason{Kind: ReasonSourcesChanged}, legacy.diff(current))
+}
+
+func TestChecksumCheckerPruneVariants(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+	tempDir := t.TempDir()
+	checker := NewChecksumChecker(tempDir, false)
+	newTask := func(version int) *ast.Task {
+		vars := ast.NewVars()
+		vars.Set("VERSION", ast.Var{Value: version})
+		return &ast.Task{
+			Task:        "build",
+			Dir:         dir,
+			Sources:     []*ast.Glob{{Glob: "*.txt"}},
+			Vars:        vars,
+			Fingerprint: &ast.Fingerprint{Vars: []string{"VERSION"}},
+		}
+	}
+
+	// The first variant is used again before a new one is written, so the
+	// second one is the least recently used
+	var versions []int
+	for version := range maxVariants {
+		versions = append(versions, version)
+	}
+	for _, version := range append(versions, 0, maxVariants) {
+		_, _, err := checker.IsUpToDate(newTask(version))
+		require.NoError(t, err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(tempDir, "checksum", "build@*"))
+	require.NoError(t, err)
+	assert.Len(t, files, maxVariants)
+	assert.FileExists(t, checker.checksumFilePath(newTask(0)))
+	assert.NoFileExists(t, checker.checksumFilePath(newTask(1)))
+	assert.FileExists(t, checker.checksumFilePath(newTask(maxVariants)))
+}
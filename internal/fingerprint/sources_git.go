@@ -0,0 +1,133 @@
+package fingerprint
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/zeebo/xxh3"
+)
+
+// NewGitChecker creates a [ChecksumChecker] that uses the object hashes stored
+// in the git index instead of reading the sources, which is a lot faster in
+// large repositories. Only sources that were modified since they were staged
+// are read. Untracked sources are ignored. Outside of a git repository, or if
+// git is not installed, it behaves exactly like the checksum method.
+func NewGitChecker(tempDir string, dry bool) *ChecksumChecker {
+	return &ChecksumChecker{
+		tempDir: tempDir,
+		dry:     dry,
+		git:     true,
+	}
+}
+
+// gitChecksums returns the checksums of the given files, using their object
+// hashes from the git index. It returns false if dir is not inside a git
+// repository.
+func gitChecksums(dir string, files []string, extra string) (*checksums, bool, error) {
+	staged, err := gitLsFiles(dir, "--stage")
+	if err != nil {
+		return nil, false, nil
+	}
+	modified, err := gitLsFiles(dir, "--modified")
+	if err != nil {
+		return nil, false, nil
+	}
+
+	index := make(map[string]string, len(staged))
+	newHash := sha1.New
+	for _, entry := range staged {
+		// Entries are in the "<mode> <object> <stage>\t<file>" format
+		info, path, ok := strings.Cut(entry, "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 3 {
+			continue
+		}
+		if fields[2] != "0" {
+			// Files with merge conflicts have to be read
+			index[path] = ""
+			continue
+		}
+		index[path] = fields[1]
+		if len(fields[1]) == sha256.Size*2 {
+			newHash = sha256.New
+		}
+	}
+	for _, path := range modified {
+		index[path] = ""
+	}
+
+	sums := &checksums{files: make(map[string]string, len(files))}
+	h := xxh3.New()
+	for _, f := range files {
+		rel := relPath(dir, f)
+		object, tracked := index[rel]
+		if !tracked && filepath.IsLocal(filepath.FromSlash(rel)) {
+			continue
+		}
+		// Files outside of dir are not listed, so they have to be read as well
+		if object == "" {
+			if object, err = gitObjectHash(newHash(), f); err != nil {
+				return nil, true, err
+			}
+		}
+		if _, err := io.WriteString(h, filepath.Base(f)+object); err != nil {
+			return nil, true, err
+		}
+		sums.files[rel] = object
+	}
+
+	if extra != "" {
+		if _, err := io.WriteString(h, "\x00"+extra); err != nil {
+			return nil, true, err
+		}
+	}
+
+	sum := h.Sum128()
+	sums.hash = fmt.Sprintf("%x%x", sum.Hi, sum.Lo)
+	return sums, true, nil
+}
+
+// gitLsFiles returns the paths, relative to dir, listed by "git ls-files" with
+// the given flag.
+func gitLsFiles(dir string, flag string) ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "-z", flag)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for entry := range bytes.SplitSeq(out, []byte{0}) {
+		if len(entry) > 0 {
+			paths = append(paths, string(entry))
+		}
+	}
+	return paths, nil
+}
+
+// gitObjectHash returns the hash git would give to the content of the file, so
+// that staging a modified file doesn't change its checksum.
+func gitObjectHash(h hash.Hash, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "blob %d\x00", info.Size())
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,94 @@
+package fingerprint
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func TestGitChecker(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	git("init", "-q")
+	writeFile("tracked.txt", "tracked")
+	writeFile("untracked.txt", "untracked")
+	git("add", "tracked.txt")
+
+	task := &ast.Task{
+		Task:    "build",
+		Dir:     dir,
+		Sources: []*ast.Glob{{Glob: "*.txt"}},
+	}
+	checker := NewGitChecker(t.TempDir(), false)
+	isUpToDate := func() (bool, *Reason) {
+		upToDate, reason, err := checker.IsUpToDate(task)
+		require.NoError(t, err)
+		return upToDate, reason
+	}
+
+	upToDate, reason := isUpToDate()
+	assert.False(t, upToDate)
+	assert.Equal(t, &Reason{Kind: ReasonNoFingerprint}, reason)
+	upToDate, _ = isUpToDate()
+	assert.True(t, upToDate)
+
+	sums, err := checker.checksums(task)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"tracked.txt": git("hash-object", "tracked.txt")}, sums.files)
+
+	// Untracked files are ignored
+	writeFile("untracked.txt", "changed")
+	upToDate, _ = isUpToDate()
+	assert.True(t, upToDate)
+
+	writeFile("tracked.txt", "changed")
+	upToDate, reason = isUpToDate()
+	assert.False(t, upToDate)
+	assert.Equal(t, []string{"tracked.txt"}, reason.Changed)
+
+	// Staging a modified file doesn't change its checksum
+	git("add", "tracked.txt")
+	upToDate, _ = isUpToDate()
+	assert.True(t, upToDate)
+}
+
+func TestGitCheckerOutsideRepository(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+	task := &ast.Task{
+		Task:    "build",
+		Dir:     dir,
+		Sources: []*ast.Glob{{Glob: "*.txt"}},
+	}
+
+	sums, err := NewGitChecker(t.TempDir(), false).checksums(task)
+	require.NoError(t, err)
+	expected, err := NewChecksumChecker(t.TempDir(), false).checksums(task)
+	require.NoError(t, err)
+	assert.Equal(t, expected, sums)
+}
@@ -6,8 +6,8 @@ import "github.com/go-task/task/v3/taskfile/ast"
 // It will always report that the task is not up-to-date.
 type NoneChecker struct{}
 
-func (NoneChecker) IsUpToDate(t *ast.Task) (bool, error) {
-	return false, nil
+func (NoneChecker) IsUpToDate(t *ast.Task) (bool, *Reason, error) {
+	return false, &Reason{Kind: ReasonMethodNone}, nil
 }
 
 func (NoneChecker) Value(t *ast.Task) (any, error) {
@@ -18,6 +18,10 @@ func (NoneChecker) OnError(t *ast.Task) error {
 	return nil
 }
 
+func (NoneChecker) OnSuccess(t *ast.Task) error {
+	return nil
+}
+
 func (NoneChecker) Kind() string {
 	return "none"
 }
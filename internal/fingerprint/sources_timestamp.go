@@ -23,18 +23,18 @@ func NewTimestampChecker(tempDir string, dry bool) *TimestampChecker {
 }
 
 // IsUpToDate implements the Checker interface
-func (checker *TimestampChecker) IsUpToDate(t *ast.Task) (bool, error) {
+func (checker *TimestampChecker) IsUpToDate(t *ast.Task) (bool, *Reason, error) {
 	if len(t.Sources) == 0 {
-		return false, nil
+		return false, &Reason{Kind: ReasonNoSourcesOrStatus}, nil
 	}
 
-	sources, err := Globs(t.Dir, t.Sources)
+	sources, err := Sources(t)
 	if err != nil {
-		return false, nil
+		return false, &Reason{Kind: ReasonSourcesError, Detail: err.Error()}, nil
 	}
 	generates, err := Globs(t.Dir, t.Generates)
 	if err != nil {
-		return false, nil
+		return false, &Reason{Kind: ReasonSourcesError, Detail: err.Error()}, nil
 	}
 
 	timestampFile := checker.timestampFilePath(t)
@@ -48,11 +48,11 @@ func (checker *TimestampChecker) IsUpToDate(t *ast.Task) (bool, error) {
 		// Create the timestamp file for the next execution when the file does not exist.
 		if !checker.dry {
 			if err := os.MkdirAll(filepath.Dir(timestampFile), 0o755); err != nil {
-				return false, err
+				return false, nil, err
 			}
 			f, err := os.Create(timestampFile)
 			if err != nil {
-				return false, err
+				return false, nil, err
 			}
 			f.Close()
 		}
@@ -64,24 +64,30 @@ func (checker *TimestampChecker) IsUpToDate(t *ast.Task) (bool, error) {
 
 	// Get the max time of the generates.
 	generateMaxTime, err := getMaxTime(generates...)
-	if err != nil || generateMaxTime.IsZero() {
-		return false, nil
+	if err != nil {
+		return false, &Reason{Kind: ReasonSourcesError, Detail: err.Error()}, nil
+	}
+	if generateMaxTime.IsZero() {
+		return false, &Reason{Kind: ReasonNoFingerprint}, nil
 	}
 
 	// Check if any of the source files is newer than the max time of the generates.
-	shouldUpdate, err := anyFileNewerThan(sources, generateMaxTime)
+	newer, err := filesNewerThan(sources, generateMaxTime)
 	if err != nil {
-		return false, nil
+		return false, &Reason{Kind: ReasonSourcesError, Detail: err.Error()}, nil
 	}
 
 	// Modify the metadata of the file to the the current time.
 	if !checker.dry {
 		if err := os.Chtimes(timestampFile, taskTime, taskTime); err != nil {
-			return false, err
+			return false, nil, err
 		}
 	}
 
-	return !shouldUpdate, nil
+	if len(newer) > 0 {
+		return false, &Reason{Kind: ReasonSourcesChanged, Changed: relPaths(t.Dir, newer)}, nil
+	}
+	return true, nil, nil
 }
 
 func (checker *TimestampChecker) Kind() string {
@@ -90,7 +96,7 @@ func (checker *TimestampChecker) Kind() string {
 
 // Value implements the Checker Interface
 func (checker *TimestampChecker) Value(t *ast.Task) (any, error) {
-	sources, err := Globs(t.Dir, t.Sources)
+	sources, err := Sources(t)
 	if err != nil {
 		return time.Now(), err
 	}
@@ -126,19 +132,20 @@ func maxTime(a, b time.Time) time.Time {
 	return b
 }
 
-// If the modification time of any of the files is newer than the the given time, returns true.
-// This function is lazy, as it stops when it finds a file newer than the given time.
-func anyFileNewerThan(files []string, givenTime time.Time) (bool, error) {
+// filesNewerThan returns the files with a modification time newer than the
+// given time.
+func filesNewerThan(files []string, givenTime time.Time) ([]string, error) {
+	var newer []string
 	for _, f := range files {
 		info, err := os.Stat(f)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 		if info.ModTime().After(givenTime) {
-			return true, nil
+			newer = append(newer, f)
 		}
 	}
-	return false, nil
+	return newer, nil
 }
 
 // OnError implements the Checker interface
@@ -146,6 +153,11 @@ func (*TimestampChecker) OnError(t *ast.Task) error {
 	return nil
 }
 
+// OnSuccess implements the Checker interface
+func (*TimestampChecker) OnSuccess(t *ast.Task) error {
+	return nil
+}
+
 func (checker *TimestampChecker) timestampFilePath(t *ast.Task) string {
 	return filepath.Join(checker.tempDir, "timestamp", normalizeFilename(t.Task))
 }
@@ -0,0 +1,175 @@
+package fingerprint
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// racyInterval is how long after its last modification a file is considered
+// to be possibly still changing. Such files are not cached, as a change made
+// within the resolution of the file system timestamps would go unnoticed.
+const racyInterval = 2 * time.Second
+
+// statCache maps the size, modification time and inode of files to their
+// checksum, so that the files that didn't change since they were last hashed
+// don't have to be read again. It also holds the checksum of all the files of
+// a list, keyed by [listKey], which is valid as long as none of them changed.
+type statCache struct {
+	path        string
+	mutex       sync.Mutex
+	old         map[string]statEntry
+	current     map[string]statEntry
+	oldSums     map[string]string
+	currentSums map[string]string
+}
+
+type statEntry struct {
+	size    int64
+	modTime int64
+	inode   uint64
+	hash    string
+}
+
+// loadStatCache reads the stat cache stored in the given file. A missing or
+// invalid file results in an empty cache.
+func loadStatCache(path string) *statCache {
+	cache := &statCache{
+		path:        path,
+		old:         map[string]statEntry{},
+		current:     map[string]statEntry{},
+		oldSums:     map[string]string{},
+		currentSums: map[string]string{},
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return cache
+	}
+	defer f.Close()
+
+	// Each line is in the "<hash> <size> <mtime> <inode> <path>" format, or
+	// in the "<hash> <key>" format for the checksums of lists of files
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), " ", 5)
+		if len(fields) == 2 {
+			cache.oldSums[fields[1]] = fields[0]
+			continue
+		}
+		if len(fields) != 5 {
+			continue
+		}
+		size, err1 := strconv.ParseInt(fields[1], 10, 64)
+		modTime, err2 := strconv.ParseInt(fields[2], 10, 64)
+		inode, err3 := strconv.ParseUint(fields[3], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		cache.old[fields[4]] = statEntry{size: size, modTime: modTime, inode: inode, hash: fields[0]}
+	}
+	return cache
+}
+
+// get returns the cached checksum of the file, if it didn't change since it
+// was hashed.
+func (c *statCache) get(path string, info os.FileInfo) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.old[path]
+	if !ok || entry != newStatEntry(info, entry.hash) {
+		return "", false
+	}
+	c.current[path] = entry
+	return entry.hash, true
+}
+
+// set records the checksum of the file. Files modified too recently are not
+// recorded.
+func (c *statCache) set(path string, info os.FileInfo, hash string) {
+	if c == nil || time.Since(info.ModTime()) < racyInterval {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.current[path] = newStatEntry(info, hash)
+}
+
+// getSum returns the cached checksum of all the files of the list with the
+// given key. It is only valid if none of the files changed, as reported by
+// [statCache.get].
+func (c *statCache) getSum(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	hash, ok := c.oldSums[key]
+	if ok {
+		c.currentSums[key] = hash
+	}
+	return hash, ok
+}
+
+// setSum records the checksum of all the given files, under the key of their
+// list. It is not recorded unless all of the files are cached.
+func (c *statCache) setSum(key, hash string, files []string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, f := range files {
+		if _, ok := c.current[f]; !ok {
+			return
+		}
+	}
+	c.currentSums[key] = hash
+}
+
+// save writes the entries used since the cache was loaded, if they changed.
+// Entries of files that are not sources anymore are dropped.
+func (c *statCache) save() error {
+	if maps.Equal(c.old, c.current) && maps.Equal(c.oldSums, c.currentSums) {
+		return nil
+	}
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(c.currentSums)) {
+		fmt.Fprintf(&b, "%s %s\n", c.currentSums[key], key)
+	}
+	for _, path := range slices.Sorted(maps.Keys(c.current)) {
+		entry := c.current[path]
+		fmt.Fprintf(&b, "%s %d %d %d %s\n", entry.hash, entry.size, entry.modTime, entry.inode, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first, as other processes may read the cache
+	tmp := fmt.Sprintf("%s.%d.tmp", c.path, os.Getpid())
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func newStatEntry(info os.FileInfo, hash string) statEntry {
+	return statEntry{
+		size:    info.Size(),
+		modTime: info.ModTime().UnixNano(),
+		inode:   inode(info),
+		hash:    hash,
+	}
+}
@@ -0,0 +1,137 @@
+package fingerprint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/xxh3"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func TestSumFilesStatCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour)
+	writeFile := func(name, content string, modTime time.Time) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	var files []string
+	for i := range 100 {
+		name := fmt.Sprintf("file%03d.txt", i)
+		writeFile(name, name, past)
+		files = append(files, filepath.Join(dir, name))
+	}
+	writeFile("recent.txt", "recent", time.Now())
+	files = append(files, filepath.Join(dir, "recent.txt"))
+
+	cachePath := filepath.Join(t.TempDir(), "stats")
+	sum := func() *checksums {
+		cache := loadStatCache(cachePath)
+		sums, err := sumFiles(dir, files, "", cache)
+		require.NoError(t, err)
+		require.NoError(t, cache.save())
+		return sums
+	}
+
+	first := sum()
+	uncached, err := sumFiles(dir, files, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, uncached, first, "the cache must not change the checksums")
+	cache := loadStatCache(cachePath)
+	assert.Len(t, cache.old, 100, "recently modified files must not be cached")
+
+	// A cached file is not read again while its size and modification time
+	// don't change
+	writeFile("file000.txt", "changed", past)
+	writeFile("file001.txt", "FILE001.TXT", past)
+	second := sum()
+	assert.Equal(t, first.files["file001.txt"], second.files["file001.txt"])
+	assert.NotEqual(t, first.files["file000.txt"], second.files["file000.txt"])
+
+	writeFile("file001.txt", "FILE001.TXT", past.Add(time.Second))
+	third := sum()
+	assert.NotEqual(t, first.files["file001.txt"], third.files["file001.txt"])
+	assert.NotEqual(t, first.hash, third.hash)
+}
+
+func TestSumFilesUnchanged(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	past := time.Now().Add(-time.Hour)
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		require.NoError(t, os.Chtimes(path, past, past))
+	}
+	writeFile("a.txt", "a")
+	writeFile("b.txt", "b")
+	files := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}
+
+	cachePath := filepath.Join(t.TempDir(), "stats")
+	sum := func(extra string) string {
+		cache := loadStatCache(cachePath)
+		sums, err := sumFiles(dir, files, extra, cache)
+		require.NoError(t, err)
+		require.NoError(t, cache.save())
+		return sums.hash
+	}
+
+	// The checksum of all the files is the one of their names and contents,
+	// including the files too large to be read ahead
+	large := strings.Repeat("c", maxPrefetchSize+1)
+	writeFile("c.txt", large)
+	uncached, err := sumFiles(dir, append(files, filepath.Join(dir, "c.txt")), "", nil)
+	require.NoError(t, err)
+	h := xxh3.New()
+	_, _ = h.WriteString("a.txtab.txtbc.txt" + large)
+	hash := h.Sum128()
+	assert.Equal(t, fmt.Sprintf("%x%x", hash.Hi, hash.Lo), uncached.hash)
+	assert.Equal(t, fmt.Sprintf("%x", xxh3.HashString(large)), uncached.files["c.txt"])
+
+	uncached, err = sumFiles(dir, files, "", nil)
+	require.NoError(t, err)
+
+	// The files are not read again while none of them changed
+	first := sum("")
+	assert.Equal(t, uncached.hash, first)
+	writeFile("b.txt", "B")
+	assert.Equal(t, first, sum(""))
+
+	// Unless the extra string changes
+	assert.NotEqual(t, first, sum("extra"))
+}
+
+func TestChecksumCheckerDryStatCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(path, []byte("a"), 0o644))
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, past, past))
+	task := &ast.Task{
+		Task:    "build",
+		Dir:     dir,
+		Sources: []*ast.Glob{{Glob: "*.txt"}},
+	}
+
+	tempDir := t.TempDir()
+	_, err := NewChecksumChecker(tempDir, true).checksums(task)
+	require.NoError(t, err)
+	assert.NoFileExists(t, NewChecksumChecker(tempDir, true).statCacheFilePath(task))
+
+	_, err = NewChecksumChecker(tempDir, false).checksums(task)
+	require.NoError(t, err)
+	assert.FileExists(t, NewChecksumChecker(tempDir, false).statCacheFilePath(task))
+}
@@ -19,7 +19,7 @@ func NewStatusChecker(logger *logger.Logger) StatusCheckable {
 	}
 }
 
-func (checker *StatusChecker) IsUpToDate(ctx context.Context, t *ast.Task) (bool, error) {
+func (checker *StatusChecker) IsUpToDate(ctx context.Context, t *ast.Task) (bool, *Reason, error) {
 	for _, s := range t.Status {
 		err := execext.RunCommand(ctx, &execext.RunCommandOptions{
 			Command: s,
@@ -28,9 +28,9 @@ func (checker *StatusChecker) IsUpToDate(ctx context.Context, t *ast.Task) (bool
 		})
 		if err != nil {
 			checker.logger.VerboseOutf(logger.Yellow, "task: status command %s exited non-zero: %s\n", s, err)
-			return false, nil
+			return false, &Reason{Kind: ReasonStatusFailed, Detail: s}, nil
 		}
 		checker.logger.VerboseOutf(logger.Yellow, "task: status command %s exited zero\n", s)
 	}
-	return true, nil
+	return true, nil, nil
 }
@@ -1,6 +1,7 @@
 package fingerprint
 
 import (
+	"cmp"
 	"context"
 
 	"github.com/go-task/task/v3/internal/logger"
@@ -55,13 +56,31 @@ func WithSourcesChecker(checker SourcesCheckable) CheckerOption {
 	}
 }
 
+// IsTaskUpToDate returns true if the task is up-to-date according to its
+// status commands and sources.
 func IsTaskUpToDate(
 	ctx context.Context,
 	t *ast.Task,
 	opts ...CheckerOption,
 ) (bool, error) {
+	reason, err := WhyNotUpToDate(ctx, t, opts...)
+	if err != nil {
+		return false, err
+	}
+	return reason == nil, nil
+}
+
+// WhyNotUpToDate returns a [Reason] describing why the task is not up-to-date,
+// or nil if it is up-to-date.
+func WhyNotUpToDate(
+	ctx context.Context,
+	t *ast.Task,
+	opts ...CheckerOption,
+) (*Reason, error) {
 	var statusUpToDate bool
+	var statusReason *Reason
 	var sourcesUpToDate bool
+	var sourcesReason *Reason
 	var err error
 
 	// Default config
@@ -88,7 +107,7 @@ func IsTaskUpToDate(
 	if config.sourcesChecker == nil {
 		config.sourcesChecker, err = NewSourcesChecker(config.method, config.tempDir, config.dry)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 	}
 
@@ -97,36 +116,35 @@ func IsTaskUpToDate(
 
 	// If status is set, check if it is up-to-date
 	if statusIsSet {
-		statusUpToDate, err = config.statusChecker.IsUpToDate(ctx, t)
+		statusUpToDate, statusReason, err = config.statusChecker.IsUpToDate(ctx, t)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 	}
 
 	// If sources is set, check if they are up-to-date
 	if sourcesIsSet {
-		sourcesUpToDate, err = config.sourcesChecker.IsUpToDate(t)
+		sourcesUpToDate, sourcesReason, err = config.sourcesChecker.IsUpToDate(t)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 	}
 
-	// If both status and sources are set, the task is up-to-date if both are up-to-date
-	if statusIsSet && sourcesIsSet {
-		return statusUpToDate && sourcesUpToDate, nil
+	// If status is set, the task is only up-to-date if the status is up-to-date
+	if statusIsSet && !statusUpToDate {
+		return cmp.Or(statusReason, &Reason{Kind: ReasonStatusFailed}), nil
 	}
 
-	// If only status is set, the task is up-to-date if the status is up-to-date
-	if statusIsSet {
-		return statusUpToDate, nil
-	}
-
-	// If only sources is set, the task is up-to-date if the sources are up-to-date
-	if sourcesIsSet {
-		return sourcesUpToDate, nil
+	// If sources is set, the task is only up-to-date if the sources are up-to-date
+	if sourcesIsSet && !sourcesUpToDate {
+		return cmp.Or(sourcesReason, &Reason{Kind: ReasonSourcesChanged}), nil
 	}
 
 	// If no status or sources are set, the task should always run
 	// i.e. it is never considered "up-to-date"
-	return false, nil
+	if !statusIsSet && !sourcesIsSet {
+		return &Reason{Kind: ReasonNoSourcesOrStatus}, nil
+	}
+
+	return nil, nil
 }
@@ -32,6 +32,7 @@ func TestIsTaskUpToDate(t *testing.T) {
 		setupMockStatusChecker  func(m *MockStatusCheckable)
 		setupMockSourcesChecker func(m *MockSourcesCheckable)
 		expected                bool
+		expectedReason          ReasonKind
 	}{
 		{
 			name: "expect FALSE when no status or sources are defined",
@@ -42,6 +43,7 @@ func TestIsTaskUpToDate(t *testing.T) {
 			setupMockStatusChecker:  nil,
 			setupMockSourcesChecker: nil,
 			expected:                false,
+			expectedReason:          ReasonNoSourcesOrStatus,
 		},
 		{
 			name: "expect TRUE when no status is defined and sources are up-to-date",
@@ -51,7 +53,7 @@ func TestIsTaskUpToDate(t *testing.T) {
 			},
 			setupMockStatusChecker: nil,
 			setupMockSourcesChecker: func(m *MockSourcesCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything).Return(true, nil)
+				m.EXPECT().IsUpToDate(mock.Anything).Return(true, nil, nil)
 			},
 			expected: true,
 		},
@@ -63,9 +65,10 @@ func TestIsTaskUpToDate(t *testing.T) {
 			},
 			setupMockStatusChecker: nil,
 			setupMockSourcesChecker: func(m *MockSourcesCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything).Return(false, nil)
+				m.EXPECT().IsUpToDate(mock.Anything).Return(false, &Reason{Kind: ReasonSourcesChanged}, nil)
 			},
-			expected: false,
+			expected:       false,
+			expectedReason: ReasonSourcesChanged,
 		},
 		{
 			name: "expect TRUE when status is up-to-date and sources are not defined",
@@ -74,7 +77,7 @@ func TestIsTaskUpToDate(t *testing.T) {
 				Sources: nil,
 			},
 			setupMockStatusChecker: func(m *MockStatusCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(true, nil)
+				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(true, nil, nil)
 			},
 			setupMockSourcesChecker: nil,
 			expected:                true,
@@ -86,10 +89,10 @@ func TestIsTaskUpToDate(t *testing.T) {
 				Sources: []*ast.Glob{{Glob: "sources"}},
 			},
 			setupMockStatusChecker: func(m *MockStatusCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(true, nil)
+				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(true, nil, nil)
 			},
 			setupMockSourcesChecker: func(m *MockSourcesCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything).Return(true, nil)
+				m.EXPECT().IsUpToDate(mock.Anything).Return(true, nil, nil)
 			},
 			expected: true,
 		},
@@ -100,12 +103,13 @@ func TestIsTaskUpToDate(t *testing.T) {
 				Sources: []*ast.Glob{{Glob: "sources"}},
 			},
 			setupMockStatusChecker: func(m *MockStatusCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(true, nil)
+				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(true, nil, nil)
 			},
 			setupMockSourcesChecker: func(m *MockSourcesCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything).Return(false, nil)
+				m.EXPECT().IsUpToDate(mock.Anything).Return(false, &Reason{Kind: ReasonSourcesChanged}, nil)
 			},
-			expected: false,
+			expected:       false,
+			expectedReason: ReasonSourcesChanged,
 		},
 		{
 			name: "expect FALSE when status is NOT up-to-date and sources are not defined",
@@ -114,10 +118,11 @@ func TestIsTaskUpToDate(t *testing.T) {
 				Sources: nil,
 			},
 			setupMockStatusChecker: func(m *MockStatusCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(false, nil)
+				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(false, &Reason{Kind: ReasonStatusFailed, Detail: "false"}, nil)
 			},
 			setupMockSourcesChecker: nil,
 			expected:                false,
+			expectedReason:          ReasonStatusFailed,
 		},
 		{
 			name: "expect FALSE when status is NOT up-to-date, but sources are up-to-date",
@@ -126,12 +131,13 @@ func TestIsTaskUpToDate(t *testing.T) {
 				Sources: []*ast.Glob{{Glob: "sources"}},
 			},
 			setupMockStatusChecker: func(m *MockStatusCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(false, nil)
+				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(false, &Reason{Kind: ReasonStatusFailed, Detail: "false"}, nil)
 			},
 			setupMockSourcesChecker: func(m *MockSourcesCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything).Return(true, nil)
+				m.EXPECT().IsUpToDate(mock.Anything).Return(true, nil, nil)
 			},
-			expected: false,
+			expected:       false,
+			expectedReason: ReasonStatusFailed,
 		},
 		{
 			name: "expect FALSE when status and sources are NOT up-to-date",
@@ -140,12 +146,13 @@ func TestIsTaskUpToDate(t *testing.T) {
 				Sources: []*ast.Glob{{Glob: "sources"}},
 			},
 			setupMockStatusChecker: func(m *MockStatusCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(false, nil)
+				m.EXPECT().IsUpToDate(mock.Anything, mock.Anything).Return(false, &Reason{Kind: ReasonStatusFailed, Detail: "false"}, nil)
 			},
 			setupMockSourcesChecker: func(m *MockSourcesCheckable) {
-				m.EXPECT().IsUpToDate(mock.Anything).Return(false, nil)
+				m.EXPECT().IsUpToDate(mock.Anything).Return(false, &Reason{Kind: ReasonSourcesChanged}, nil)
 			},
-			expected: false,
+			expected:       false,
+			expectedReason: ReasonStatusFailed,
 		},
 	}
 	for _, tt := range tests {
@@ -170,6 +177,20 @@ func TestIsTaskUpToDate(t *testing.T) {
 			)
 			require.NoError(t, err)
 			assert.Equal(t, tt.expected, result)
+
+			reason, err := WhyNotUpToDate(
+				t.Context(),
+				tt.task,
+				WithStatusChecker(mockStatusChecker),
+				WithSourcesChecker(mockSourcesChecker),
+			)
+			require.NoError(t, err)
+			if tt.expectedReason == "" {
+				assert.Nil(t, reason)
+			} else {
+				require.NotNil(t, reason)
+				assert.Equal(t, tt.expectedReason, reason.Kind)
+			}
 		})
 	}
 }
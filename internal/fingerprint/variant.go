@@ -0,0 +1,45 @@
+package fingerprint
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zeebo/xxh3"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// variant returns a checksum of the variables, environment variables and
+// commands selected by the fingerprint of the task, or an empty string if it
+// doesn't select any.
+func variant(t *ast.Task) string {
+	f := t.Fingerprint
+	if f == nil || (len(f.Vars) == 0 && len(f.Env) == 0 && !f.Cmds) {
+		return ""
+	}
+
+	h := xxh3.New()
+	for _, name := range f.Vars {
+		if v, ok := t.Vars.Get(name); ok {
+			fmt.Fprintf(h, "var %s=%v\n", name, v.Value)
+		} else {
+			fmt.Fprintf(h, "var %s\n", name)
+		}
+	}
+	for _, name := range f.Env {
+		// The environment of the task takes precedence over the one of Task
+		if v, ok := t.Env.Get(name); ok {
+			fmt.Fprintf(h, "env %s=%v\n", name, v.Value)
+		} else if value, ok := os.LookupEnv(name); ok {
+			fmt.Fprintf(h, "env %s=%s\n", name, value)
+		} else {
+			fmt.Fprintf(h, "env %s\n", name)
+		}
+	}
+	if f.Cmds {
+		for _, cmd := range t.Cmds {
+			fmt.Fprintf(h, "cmd %q %q\n", cmd.Cmd, cmd.Task)
+		}
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
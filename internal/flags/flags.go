@@ -2,9 +2,12 @@ package flags
 
 import (
 	"cmp"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"time"
 
@@ -16,6 +19,7 @@ import (
 	"github.com/go-task/task/v3/experiments"
 	"github.com/go-task/task/v3/internal/env"
 	"github.com/go-task/task/v3/internal/sort"
+	"github.com/go-task/task/v3/taskfile"
 	"github.com/go-task/task/v3/taskfile/ast"
 	"github.com/go-task/task/v3/taskrc"
 	taskrcast "github.com/go-task/task/v3/taskrc/ast"
@@ -53,6 +57,8 @@ var (
 	ListJson            bool
 	TaskSort            string
 	Status              bool
+	Plan                bool
+	Why                 bool
 	NoStatus            bool
 	Nested              bool
 	Insecure            bool
@@ -67,6 +73,8 @@ var (
 	Summary             bool
 	ExitCode            bool
 	Parallel            bool
+	Recursive           bool
+	AffectedSince       string
 	Concurrency         int
 	Dir                 string
 	Entrypoint          string
@@ -74,16 +82,29 @@ var (
 	Color               bool
 	Interval            time.Duration
 	Failfast            bool
+	TaskTimeout         time.Duration
+	BuildCacheDir       string
+	BuildCacheURL       string
+	BuildCacheToken     string
 	Global              bool
 	Experiments         bool
 	Download            bool
 	Offline             bool
+	Lock                bool
+	UpdateLock          bool
+	Vendor              bool
 	TrustedHosts        []string
+	TrustedKeys         []string
+	Sign                bool
+	SigningKey          string
 	ClearCache          bool
+	Cache               string
 	Timeout             time.Duration
 	CacheExpiryDuration time.Duration
 	RemoteCacheDir      string
 	CACert              string
+	Mirrors             map[string]string
+	Credentials         map[string]taskfile.Credentials
 	Cert                string
 	CertKey             string
 	Interactive         bool
@@ -125,9 +146,11 @@ func init() {
 	pflag.StringVar(&Completion, "completion", "", "Generates shell completion script.")
 	pflag.BoolVarP(&List, "list", "l", false, "Lists tasks with description of current Taskfile.")
 	pflag.BoolVarP(&ListAll, "list-all", "a", false, "Lists tasks with or without a description.")
-	pflag.BoolVarP(&ListJson, "json", "j", false, "Formats task list as JSON.")
+	pflag.BoolVarP(&ListJson, "json", "j", false, "Formats task list or plan as JSON.")
 	pflag.StringVar(&TaskSort, "sort", "", "Changes the order of the tasks when listed. [default|alphanumeric|none].")
 	pflag.BoolVar(&Status, "status", false, "Exits with non-zero exit code if any of the given tasks is not up-to-date.")
+	pflag.BoolVar(&Why, "why", false, "Explains why the given tasks are not up-to-date.")
+	pflag.BoolVar(&Plan, "plan", false, "Shows which tasks would run and why, without running them.")
 	pflag.BoolVar(&NoStatus, "no-status", false, "Ignore status when listing tasks as JSON")
 	pflag.BoolVar(&Nested, "nested", false, "Nest namespaces when listing tasks as JSON")
 	pflag.BoolVar(&Insecure, "insecure", getConfig(config, "REMOTE_INSECURE", func() *bool { return config.Remote.Insecure }, false), "Forces Task to download Taskfiles over insecure connections.")
@@ -138,6 +161,8 @@ func init() {
 	pflag.BoolVarP(&AssumeYes, "yes", "y", getConfig(config, "ASSUME_YES", func() *bool { return nil }, false), "Assume \"yes\" as answer to all prompts.")
 	pflag.BoolVar(&Interactive, "interactive", getConfig(config, "INTERACTIVE", func() *bool { return config.Interactive }, false), "Prompt for missing required variables.")
 	pflag.BoolVarP(&Parallel, "parallel", "p", false, "Executes tasks provided on command line in parallel.")
+	pflag.BoolVarP(&Recursive, "recursive", "r", false, "Executes tasks provided on command line in every included namespace that defines them, in dependency order.")
+	pflag.StringVar(&AffectedSince, "affected-since", "", "Only runs or lists the tasks whose sources, or the sources of their dependencies, changed since the given git ref.")
 	pflag.BoolVarP(&Dry, "dry", "n", getConfig(config, "DRY", func() *bool { return nil }, false), "Compiles and prints tasks in the order that they would be run, without executing them.")
 	pflag.BoolVar(&Summary, "summary", false, "Show summary about a task.")
 	pflag.BoolVarP(&ExitCode, "exit-code", "x", false, "Pass-through the exit code of the task command.")
@@ -151,6 +176,11 @@ func init() {
 	pflag.IntVarP(&Concurrency, "concurrency", "C", getConfig(config, "CONCURRENCY", func() *int { return config.Concurrency }, 0), "Limit number of tasks to run concurrently.")
 	pflag.DurationVarP(&Interval, "interval", "I", 0, "Interval to watch for changes.")
 	pflag.BoolVarP(&Failfast, "failfast", "F", getConfig(config, "FAILFAST", func() *bool { return &config.Failfast }, false), "When running tasks in parallel, stop all tasks if one fails.")
+	pflag.DurationVar(&TaskTimeout, "task-timeout", getConfig(config, "TASK_TIMEOUT", func() *time.Duration { return config.TaskTimeout }, 0), "Default timeout for each task. Disabled by default.")
+	pflag.StringVar(&BuildCacheDir, "build-cache-dir", getConfig(config, "BUILD_CACHE_DIR", func() *string { return config.BuildCache.Dir }, ""), "Directory of the build cache for tasks with \"cache: true\".")
+	pflag.StringVar(&BuildCacheURL, "build-cache-url", getConfig(config, "BUILD_CACHE_URL", func() *string { return config.BuildCache.URL }, ""), "URL of an HTTP server to share the build cache.")
+	// The token has no flag, so that it doesn't show up in the list of processes
+	BuildCacheToken = getConfig(config, "BUILD_CACHE_TOKEN", func() *string { return config.BuildCache.Token }, "")
 	pflag.BoolVarP(&Global, "global", "g", false, "Runs global Taskfile, from $HOME/{T,t}askfile.{yml,yaml}.")
 	pflag.BoolVar(&Experiments, "experiments", false, "Lists all the available experiments and whether or not they are enabled.")
 
@@ -166,11 +196,20 @@ func init() {
 	if experiments.RemoteTaskfiles.Enabled() {
 		pflag.BoolVar(&Download, "download", false, "Downloads a cached version of a remote Taskfile.")
 		pflag.BoolVar(&Offline, "offline", getConfig(config, "REMOTE_OFFLINE", func() *bool { return config.Remote.Offline }, false), "Forces Task to only use local or cached Taskfiles.")
+		pflag.BoolVar(&Lock, "lock", false, "Writes the URL, commit and checksum of remote Taskfiles to Taskfile.lock.")
+		pflag.BoolVar(&UpdateLock, "update-lock", false, "Writes Taskfile.lock again from the latest version of remote Taskfiles.")
+		pflag.BoolVar(&Vendor, "vendor", false, "Downloads remote Taskfiles into .task/vendor, to be used instead of the network.")
 		pflag.StringSliceVar(&TrustedHosts, "trusted-hosts", getConfig(config, "REMOTE_TRUSTED_HOSTS", func() *[]string { return &config.Remote.TrustedHosts }, nil), "List of trusted hosts for remote Taskfiles (comma-separated).")
+		pflag.StringSliceVar(&TrustedKeys, "trusted-keys", getConfig(config, "REMOTE_TRUSTED_KEYS", func() *[]string { return &config.Remote.TrustedKeys }, nil), "List of public keys remote Taskfiles must be signed with (comma-separated).")
+		pflag.BoolVar(&Sign, "sign", false, "Signs the given Taskfiles with the key set by --signing-key.")
+		pflag.StringVar(&SigningKey, "signing-key", getConfig(config, "REMOTE_SIGNING_KEY", func() *string { return config.Remote.SigningKey }, ""), "Path to the SSH private key used by --sign.")
 		pflag.DurationVar(&Timeout, "timeout", getConfig(config, "REMOTE_TIMEOUT", func() *time.Duration { return config.Remote.Timeout }, time.Second*10), "Timeout for downloading remote Taskfiles.")
-		pflag.BoolVar(&ClearCache, "clear-cache", false, "Clear the remote cache.")
+		pflag.BoolVar(&ClearCache, "clear-cache", false, "Clear the remote cache. Same as --cache clear.")
+		pflag.StringVar(&Cache, "cache", "", "Manages the remote cache: \"list\" lists its entries, \"prune\" removes the expired ones and \"clear\" removes all of them.")
 		pflag.DurationVar(&CacheExpiryDuration, "expiry", getConfig(config, "REMOTE_CACHE_EXPIRY", func() *time.Duration { return config.Remote.CacheExpiry }, 0), "Expiry duration for cached remote Taskfiles.")
 		pflag.StringVar(&RemoteCacheDir, "remote-cache-dir", getConfig(config, "REMOTE_CACHE_DIR", func() *string { return config.Remote.CacheDir }, env.GetTaskEnv("REMOTE_DIR")), "Directory to cache remote Taskfiles.")
+		pflag.StringToStringVar(&Mirrors, "mirrors", getConfig(config, "", func() *map[string]string { return &config.Remote.Mirrors }, nil), "URL prefixes of remote Taskfiles to download from a mirror (e.g. https://github.com/=https://mirror.example.com/).")
+		Credentials = getCredentials(config)
 		pflag.StringVar(&CACert, "cacert", getConfig(config, "REMOTE_CACERT", func() *string { return config.Remote.CACert }, ""), "Path to a custom CA certificate for HTTPS connections.")
 		pflag.StringVar(&Cert, "cert", getConfig(config, "REMOTE_CERT", func() *string { return config.Remote.Cert }, ""), "Path to a client certificate for HTTPS connections.")
 		pflag.StringVar(&CertKey, "cert-key", getConfig(config, "REMOTE_CERT_KEY", func() *string { return config.Remote.CertKey }, ""), "Path to a client certificate key for HTTPS connections.")
@@ -210,6 +249,34 @@ func Validate() error {
 		return errors.New("task: You can't set both --download and --clear-cache flags")
 	}
 
+	if Cache != "" && !slices.Contains([]string{"list", "prune", "clear"}, Cache) {
+		return fmt.Errorf("task: Unknown cache operation %q, expected \"list\", \"prune\" or \"clear\"", Cache)
+	}
+
+	if Download && Cache != "" {
+		return errors.New("task: You can't set both --download and --cache flags")
+	}
+
+	if (Lock || UpdateLock) && Offline {
+		return errors.New("task: You can't set --lock or --update-lock with --offline")
+	}
+
+	if Vendor && Offline {
+		return errors.New("task: You can't set both --vendor and --offline flags")
+	}
+
+	if Sign && SigningKey == "" {
+		return errors.New("task: You need to set --signing-key to use --sign")
+	}
+
+	if Recursive && Watch {
+		return errors.New("task: You can't set both --recursive and --watch flags")
+	}
+
+	if AffectedSince != "" && Watch {
+		return errors.New("task: You can't set both --affected-since and --watch flags")
+	}
+
 	if Global && Dir != "" {
 		return errors.New("task: You can't set both --global and --dir")
 	}
@@ -230,8 +297,8 @@ func Validate() error {
 		return errors.New("task: cannot use --list and --list-all at the same time")
 	}
 
-	if ListJson && !List && !ListAll {
-		return errors.New("task: --json only applies to --list or --list-all")
+	if ListJson && !List && !ListAll && !Plan {
+		return errors.New("task: --json only applies to --list, --list-all or --plan")
 	}
 
 	if NoStatus && !ListJson {
@@ -285,11 +352,17 @@ func (o *flagsOption) ApplyToExecutor(e *task.Executor) {
 		task.WithInsecure(Insecure),
 		task.WithDownload(Download),
 		task.WithOffline(Offline),
+		task.WithLock(Lock),
+		task.WithUpdateLock(UpdateLock),
+		task.WithVendor(Vendor),
+		task.WithTrustedKeys(TrustedKeys),
 		task.WithTrustedHosts(TrustedHosts),
 		task.WithTimeout(Timeout),
 		task.WithCacheExpiryDuration(CacheExpiryDuration),
 		task.WithRemoteCacheDir(RemoteCacheDir),
 		task.WithCACert(CACert),
+		task.WithMirrors(Mirrors),
+		task.WithCredentials(Credentials),
 		task.WithCert(Cert),
 		task.WithCertKey(CertKey),
 		task.WithWatch(Watch),
@@ -301,6 +374,8 @@ func (o *flagsOption) ApplyToExecutor(e *task.Executor) {
 		task.WithDry(Dry || Status),
 		task.WithSummary(Summary),
 		task.WithParallel(Parallel),
+		task.WithRecursive(Recursive),
+		task.WithAffectedSince(AffectedSince),
 		task.WithColor(Color),
 		task.WithConcurrency(Concurrency),
 		task.WithInterval(Interval),
@@ -308,6 +383,10 @@ func (o *flagsOption) ApplyToExecutor(e *task.Executor) {
 		task.WithTaskSorter(sorter),
 		task.WithVersionCheck(true),
 		task.WithFailfast(Failfast),
+		task.WithTaskTimeout(TaskTimeout),
+		task.WithBuildCacheDir(BuildCacheDir),
+		task.WithBuildCacheURL(BuildCacheURL),
+		task.WithBuildCacheToken(BuildCacheToken),
 	)
 }
 
@@ -326,6 +405,39 @@ func getConfig[T any](config *taskrcast.TaskRC, envKey string, fieldFunc func()
 	return fallback
 }
 
+// getCredentials returns the credentials of the hosts of remote Taskfiles set
+// in the config, with the environment variables referenced as ${NAME} in their
+// values expanded.
+func getCredentials(config *taskrcast.TaskRC) map[string]taskfile.Credentials {
+	if config == nil || len(config.Remote.Auth) == 0 {
+		return nil
+	}
+	credentials := make(map[string]taskfile.Credentials, len(config.Remote.Auth))
+	for host, auth := range config.Remote.Auth {
+		headers := make(map[string]string, len(auth.Headers))
+		for name, value := range auth.Headers {
+			headers[name] = expandEnvRefs(value)
+		}
+		credentials[host] = taskfile.Credentials{
+			Username: expandEnvRefs(auth.Username),
+			Password: expandEnvRefs(auth.Password),
+			Token:    expandEnvRefs(auth.Token),
+			Headers:  headers,
+		}
+	}
+	return credentials
+}
+
+var envRefRegexp = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// expandEnvRefs expands the environment variables referenced as ${NAME} in the
+// given value. Other dollar signs are kept, as secrets may contain them.
+func expandEnvRefs(value string) string {
+	return envRefRegexp.ReplaceAllStringFunc(value, func(ref string) string {
+		return os.Getenv(envRefRegexp.FindStringSubmatch(ref)[1])
+	})
+}
+
 // getEnvAs parses a TASK_ prefixed env var as type T
 func getEnvAs[T any](envKey string) (T, bool) {
 	var zero T
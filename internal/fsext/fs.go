@@ -3,6 +3,8 @@ package fsext
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/go-task/task/v3/internal/filepathext"
 	"github.com/go-task/task/v3/internal/sysinfo"
@@ -114,6 +116,32 @@ func SearchAll(entrypoint, dir string, possibleFilenames []string) ([]string, er
 	return append(entrypoints, paths...), err
 }
 
+// IsGlob reports whether the given path contains glob wildcards.
+func IsGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// SearchGlob expands the given glob pattern and checks if each match is a file
+// or a directory containing one of the possible filenames, like SearchPath
+// does. Matches that are neither are skipped. The absolute path to each file
+// found is added to a list, which is returned sorted and without duplicates.
+func SearchGlob(pattern string, possibleFilenames []string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, match := range matches {
+		path, err := SearchPath(match, possibleFilenames)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return slices.Compact(paths), nil
+}
+
 // SearchPath will check if a file at the given path exists or not. If it does,
 // it will return the path to it. If it does not, it will search for any files
 // at the given path with any of the given possible names. If any of these match
@@ -141,6 +141,43 @@ func TestSearch(t *testing.T) {
 	}
 }
 
+func TestSearchGlob(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{
+		"packages/api/Taskfile.yml",
+		"packages/web/Taskfile.yaml",
+		"packages/docs/README.md",
+		"packages/Taskfile.yml",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, nil, 0o644))
+	}
+	possibleFilenames := []string{"Taskfile.yml", "Taskfile.yaml"}
+
+	// Directories are searched for the possible filenames
+	paths, err := SearchGlob(filepath.Join(dir, "packages", "*"), possibleFilenames)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, "packages", "Taskfile.yml"),
+		filepath.Join(dir, "packages", "api", "Taskfile.yml"),
+		filepath.Join(dir, "packages", "web", "Taskfile.yaml"),
+	}, paths)
+
+	paths, err = SearchGlob(filepath.Join(dir, "packages", "*", "Taskfile.yml"), possibleFilenames)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "packages", "api", "Taskfile.yml")}, paths)
+
+	paths, err = SearchGlob(filepath.Join(dir, "missing", "*"), possibleFilenames)
+	require.NoError(t, err)
+	require.Empty(t, paths)
+
+	require.True(t, IsGlob("./packages/*/Taskfile.yml"))
+	require.False(t, IsGlob("./packages/api/Taskfile.yml"))
+}
+
 func TestResolveDir(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,211 @@
+// Package ignore matches paths against the patterns of .gitignore and
+// .taskignore files.
+package ignore
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Files lists the names of the files patterns are read from. When both exist
+// in the same directory, the patterns of the latter take precedence.
+var Files = []string{".gitignore", ".taskignore"}
+
+// A Matcher reports whether paths are ignored by the ignore files in the
+// directories between its root and the path. Ignore files are read lazily and
+// only once.
+type Matcher struct {
+	root  string
+	mutex sync.Mutex
+	rules map[string][]*rule
+}
+
+// New creates a new [Matcher] for paths inside the given directory. The ignore
+// files of its parent directories are used as well, up to the root of the git
+// repository containing it, if any.
+func New(dir string) *Matcher {
+	dir, _ = filepath.Abs(dir)
+	root := dir
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			root = d
+			break
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	return &Matcher{
+		root:  root,
+		rules: map[string][]*rule{},
+	}
+}
+
+// Match reports whether the given path is ignored. Only the last component of
+// the path is matched, as if its parent directories were not ignored. The .git
+// directory is always ignored.
+func (m *Matcher) Match(path string, isDir bool) bool {
+	if filepath.Base(path) == ".git" {
+		return true
+	}
+	path, _ = filepath.Abs(path)
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return false
+	}
+
+	ignored := false
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	dir := m.root
+	for i := range segments {
+		relToDir := strings.Join(segments[i:], "/")
+		for _, r := range m.rulesFor(dir) {
+			if r.match(relToDir, isDir) {
+				ignored = !r.negate
+			}
+		}
+		dir = filepath.Join(dir, segments[i])
+	}
+	return ignored
+}
+
+// ReadDir works like [os.ReadDir], but skips ignored entries. It can be used to
+// expand globs without walking ignored directories.
+func (m *Matcher) ReadDir(dir string) ([]fs.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	filtered := entries[:0]
+	for _, entry := range entries {
+		if !m.Match(filepath.Join(dir, entry.Name()), entry.IsDir()) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
+
+func (m *Matcher) rulesFor(dir string) []*rule {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if rules, ok := m.rules[dir]; ok {
+		return rules
+	}
+	var rules []*rule
+	for _, name := range Files {
+		rules = append(rules, readRules(filepath.Join(dir, name))...)
+	}
+	m.rules[dir] = rules
+	return rules
+}
+
+func readRules(path string) []*rule {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var rules []*rule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if r := parseRule(scanner.Text()); r != nil {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+type rule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+func (r *rule) match(path string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	return r.re.MatchString(path)
+}
+
+// parseRule parses a line of an ignore file, following the format of
+// .gitignore files. It returns nil for blank lines, comments and invalid
+// patterns.
+func parseRule(line string) *rule {
+	line = strings.TrimSuffix(line, "\r")
+	if !strings.HasSuffix(line, `\ `) {
+		line = strings.TrimRight(line, " ")
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	r := &rule{}
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return nil
+	}
+
+	// Patterns with a slash are relative to the directory of the ignore file,
+	// the others match at any depth
+	var b strings.Builder
+	if strings.Contains(line, "/") {
+		b.WriteString("^")
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case strings.HasPrefix(line[i:], "**/") && (i == 0 || line[i-1] == '/'):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case line[i:] == "**" && i > 0 && line[i-1] == '/':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(line[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := line[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(line):
+			i++
+			b.WriteString(regexp.QuoteMeta(line[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(line[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil
+	}
+	r.re = re
+	return r
+}
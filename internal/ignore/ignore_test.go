@@ -0,0 +1,108 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		match   bool
+	}{
+		{"node_modules", "node_modules", true, true},
+		{"node_modules", "web/node_modules", true, true},
+		{"node_modules/", "node_modules", false, false},
+		{"/build", "build", true, true},
+		{"/build", "web/build", true, false},
+		{"*.log", "debug.log", false, true},
+		{"*.log", "logs/debug.log", false, true},
+		{"*.log", "debug.log.txt", false, false},
+		{"doc/*.txt", "doc/notes.txt", false, true},
+		{"doc/*.txt", "doc/server/notes.txt", false, false},
+		{"**/gen", "a/b/gen", true, true},
+		{"**/gen", "gen", true, true},
+		{"a/**/b", "a/b", true, true},
+		{"a/**/b", "a/x/y/b", true, true},
+		{"out/**", "out/a/b.txt", false, true},
+		{"out/**", "out", true, false},
+		{"file?.txt", "file1.txt", false, true},
+		{"file?.txt", "file10.txt", false, false},
+		{"[abc].go", "b.go", false, true},
+		{"[!abc].go", "b.go", false, false},
+		{`\#hash`, "#hash", false, true},
+		{"trailing   ", "trailing", false, true},
+	}
+	for _, test := range tests {
+		r := parseRule(test.pattern)
+		require.NotNil(t, r, test.pattern)
+		assert.Equal(t, test.match, r.match(test.path, test.isDir), "%q on %q", test.pattern, test.path)
+	}
+
+	for _, line := range []string{"", "   ", "# comment", "!", "/"} {
+		assert.Nil(t, parseRule(line), line)
+	}
+	assert.True(t, parseRule("!keep.log").negate)
+}
+
+func TestMatcher(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0o755))
+	writeFile(".gitignore", "*.log\nnode_modules/\n/build\n")
+	writeFile(".taskignore", "!keep.log\n")
+	writeFile("web/.gitignore", "dist/\n!web.log\n")
+	writeFile("web/sub/.taskignore", "*.tmp\n")
+
+	m := New(filepath.Join(dir, "web"))
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{".git", true, true},
+		{"main.go", false, false},
+		{"debug.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"web/build", true, false},
+		{"web/node_modules", true, true},
+		{"web/dist", true, true},
+		{"dist", true, false},
+		{"web/web.log", false, false},
+		{"web/other.log", false, true},
+		{"web/sub/a.tmp", false, true},
+		{"web/a.tmp", false, false},
+	}
+	for _, test := range tests {
+		path := filepath.Join(dir, filepath.FromSlash(test.path))
+		assert.Equal(t, test.ignored, m.Match(path, test.isDir), test.path)
+	}
+	assert.False(t, m.Match(filepath.Join(filepath.Dir(dir), "outside.log"), false))
+
+	writeFile("web/app.js", "")
+	writeFile("web/app.log", "")
+	writeFile("web/dist/app.js", "")
+	entries, err := m.ReadDir(filepath.Join(dir, "web"))
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{".gitignore", "app.js", "sub"}, slices.Sorted(slices.Values(names)))
+}
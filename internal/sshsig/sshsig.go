@@ -0,0 +1,154 @@
+// Package sshsig creates and verifies detached SSH signatures, in the format
+// used by "ssh-keygen -Y sign" and git.
+package sshsig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	magic         = "SSHSIG"
+	version       = 1
+	hashAlgorithm = "sha512"
+	pemType       = "SSH SIGNATURE"
+)
+
+// ErrInvalid is returned when a signature can't be parsed or doesn't match the
+// message.
+var ErrInvalid = errors.New("sshsig: invalid signature")
+
+type signature struct {
+	Magic         [6]byte
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+type signedData struct {
+	Magic         [6]byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// Sign signs the message with the given signer and returns the armored
+// signature. The namespace prevents signatures from being reused for another
+// purpose.
+func Sign(signer ssh.Signer, namespace string, message []byte) ([]byte, error) {
+	data := dataToSign(namespace, message)
+
+	var sig *ssh.Signature
+	var err error
+	if algSigner, ok := signer.(ssh.AlgorithmSigner); ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// SHA-1 RSA signatures are not accepted by ssh-keygen
+		sig, err = algSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = signer.Sign(rand.Reader, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blob := ssh.Marshal(signature{
+		Magic:         [6]byte([]byte(magic)),
+		Version:       version,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     namespace,
+		HashAlgorithm: hashAlgorithm,
+		Signature:     ssh.Marshal(sig),
+	})
+	return armor(blob), nil
+}
+
+// Verify verifies that the armored signature is a valid signature of the
+// message in the given namespace, and returns the public key that signed it.
+// The caller is responsible for checking that the key is trusted.
+func Verify(armored []byte, namespace string, message []byte) (ssh.PublicKey, error) {
+	block, _ := pem.Decode(armored)
+	if block == nil || block.Type != pemType {
+		return nil, ErrInvalid
+	}
+	var sig signature
+	if err := ssh.Unmarshal(block.Bytes, &sig); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if string(sig.Magic[:]) != magic || sig.Version != version {
+		return nil, ErrInvalid
+	}
+	if sig.Namespace != namespace {
+		return nil, fmt.Errorf("%w: unexpected namespace %q", ErrInvalid, sig.Namespace)
+	}
+	if sig.HashAlgorithm != hashAlgorithm && sig.HashAlgorithm != "sha256" {
+		return nil, fmt.Errorf("%w: unsupported hash algorithm %q", ErrInvalid, sig.HashAlgorithm)
+	}
+	publicKey, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	var s ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &s); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	data := ssh.Marshal(signedData{
+		Magic:         [6]byte([]byte(magic)),
+		Namespace:     namespace,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          hash(sig.HashAlgorithm, message),
+	})
+	if err := publicKey.Verify(data, &s); err != nil {
+		return nil, ErrInvalid
+	}
+	return publicKey, nil
+}
+
+// Equal reports whether the given public keys are the same.
+func Equal(a, b ssh.PublicKey) bool {
+	return bytes.Equal(a.Marshal(), b.Marshal())
+}
+
+func dataToSign(namespace string, message []byte) []byte {
+	return ssh.Marshal(signedData{
+		Magic:         [6]byte([]byte(magic)),
+		Namespace:     namespace,
+		HashAlgorithm: hashAlgorithm,
+		Hash:          hash(hashAlgorithm, message),
+	})
+}
+
+func hash(algorithm string, message []byte) []byte {
+	if algorithm == "sha256" {
+		sum := sha256.Sum256(message)
+		return sum[:]
+	}
+	sum := sha512.Sum512(message)
+	return sum[:]
+}
+
+// armor encodes the signature like ssh-keygen does, with lines of 70
+// characters.
+func armor(blob []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var b bytes.Buffer
+	b.WriteString("-----BEGIN " + pemType + "-----\n")
+	for len(encoded) > 70 {
+		b.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	b.WriteString(encoded + "\n")
+	b.WriteString("-----END " + pemType + "-----\n")
+	return b.Bytes()
+}
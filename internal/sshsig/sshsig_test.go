@@ -0,0 +1,90 @@
+package sshsig
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for _, key := range []any{ed25519Key, rsaKey} {
+		signer, err := ssh.NewSignerFromKey(key)
+		require.NoError(t, err)
+
+		message := []byte("version: '3'\n")
+		sig, err := Sign(signer, "task", message)
+		require.NoError(t, err)
+
+		publicKey, err := Verify(sig, "task", message)
+		require.NoError(t, err)
+		assert.True(t, Equal(signer.PublicKey(), publicKey))
+
+		_, err = Verify(sig, "task", []byte("version: '2'\n"))
+		require.ErrorIs(t, err, ErrInvalid)
+		_, err = Verify(sig, "git", message)
+		require.ErrorIs(t, err, ErrInvalid)
+		_, err = Verify([]byte("not a signature"), "task", message)
+		require.ErrorIs(t, err, ErrInvalid)
+	}
+}
+
+func TestSSHKeygenCompatibility(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_ed25519")
+	filePath := filepath.Join(dir, "Taskfile.yml")
+	require.NoError(t, os.WriteFile(filePath, []byte("version: '3'\n"), 0o644))
+	run := func(args ...string) {
+		out, err := exec.Command("ssh-keygen", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	run("-q", "-t", "ed25519", "-N", "", "-f", keyPath)
+	run("-Y", "sign", "-n", "task", "-f", keyPath, filePath)
+
+	sig, err := os.ReadFile(filePath + ".sig")
+	require.NoError(t, err)
+	publicKey, err := Verify(sig, "task", []byte("version: '3'\n"))
+	require.NoError(t, err)
+
+	authorizedKey, err := os.ReadFile(keyPath + ".pub")
+	require.NoError(t, err)
+	expected, _, _, _, err := ssh.ParseAuthorizedKey(authorizedKey)
+	require.NoError(t, err)
+	assert.True(t, Equal(expected, publicKey))
+
+	// Signatures created by Sign can be verified by ssh-keygen
+	privateKey, err := os.ReadFile(keyPath)
+	require.NoError(t, err)
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	require.NoError(t, err)
+	sig, err = Sign(signer, "task", []byte("version: '3'\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filePath+".sig", sig, 0o644))
+	allowedSigners := filepath.Join(dir, "allowed_signers")
+	require.NoError(t, os.WriteFile(allowedSigners, append([]byte("task@example.com "), authorizedKey...), 0o644))
+	cmd := exec.Command("ssh-keygen", "-Y", "verify", "-f", allowedSigners, "-I", "task@example.com", "-n", "task", "-s", filePath+".sig")
+	cmd.Stdin, err = os.Open(filePath)
+	require.NoError(t, err)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
@@ -281,6 +281,7 @@ func isEnvVar(key string, envVars map[string]bool) bool {
 		key == "TASK" ||
 		key == "TASKFILE" ||
 		key == "TASKFILE_DIR" ||
+		key == "TASKFILE_COMMIT" ||
 		key == "USER_WORKING_DIR" ||
 		key == "ALIAS" ||
 		key == "MATCH" {
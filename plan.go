@@ -0,0 +1,248 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/env"
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/fingerprint"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// PlanStatus describes what would happen to a task if it was run.
+type PlanStatus string
+
+const (
+	// PlanStatusRun means that the commands of the task would run.
+	PlanStatusRun PlanStatus = "run"
+	// PlanStatusUpToDate means that the task is up-to-date and its commands
+	// would be skipped. Its dependencies would still run.
+	PlanStatusUpToDate PlanStatus = "up-to-date"
+	// PlanStatusSkipped means that the task would be skipped entirely, for
+	// example because of its platforms or its if condition.
+	PlanStatusSkipped PlanStatus = "skipped"
+	// PlanStatusDeduplicated means that the same task is already part of the
+	// plan and would only run once because of its run mode.
+	PlanStatusDeduplicated PlanStatus = "deduplicated"
+	// PlanStatusFail means that the task would fail before running any
+	// commands because one of its preconditions is not met.
+	PlanStatusFail PlanStatus = "fail"
+)
+
+// PlanNode describes a task in an execution plan and the tasks it would run.
+type PlanNode struct {
+	Task   string      `json:"task"`
+	Status PlanStatus  `json:"status"`
+	Reason string      `json:"reason,omitempty"`
+	Cmds   []string    `json:"cmds,omitempty"`
+	Deps   []*PlanNode `json:"deps,omitempty"`
+	Calls  []*PlanNode `json:"calls,omitempty"`
+}
+
+// Plan compiles the dependency graph of the given calls without running any
+// commands and returns a node for each call describing whether it would run
+// and why. Dynamic variables, if conditions, preconditions and status checks
+// are still evaluated, exactly like they would be by [Executor.Run].
+func (e *Executor) Plan(ctx context.Context, calls ...*Call) ([]*PlanNode, error) {
+	p := &planner{
+		e:         e,
+		seen:      map[string]bool{},
+		callCount: map[string]int{},
+	}
+	nodes := make([]*PlanNode, 0, len(calls))
+	for _, call := range calls {
+		if _, err := e.GetTask(call); err != nil {
+			return nil, err
+		}
+		node, err := p.plan(ctx, call)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// PrintPlan prints the execution plan of the given calls, either as a tree or
+// as JSON.
+func (e *Executor) PrintPlan(ctx context.Context, asJSON bool, calls ...*Call) error {
+	nodes, err := e.Plan(ctx, calls...)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		encoder := json.NewEncoder(e.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(nodes)
+	}
+	for _, node := range nodes {
+		e.printPlanNode(node, "", "")
+	}
+	return nil
+}
+
+func (e *Executor) printPlanNode(node *PlanNode, prefix, childPrefix string) {
+	e.Logger.Outf(logger.Default, "%s", prefix)
+	e.Logger.Outf(planStatusColor(node.Status), "%s", node.Task)
+	if node.Reason != "" {
+		e.Logger.Outf(logger.Default, " (%s: %s)\n", node.Status, node.Reason)
+	} else {
+		e.Logger.Outf(logger.Default, " (%s)\n", node.Status)
+	}
+
+	children := slices.Concat(node.Deps, node.Calls)
+	for i, child := range children {
+		if i == len(children)-1 {
+			e.printPlanNode(child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			e.printPlanNode(child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+func planStatusColor(status PlanStatus) logger.Color {
+	switch status {
+	case PlanStatusRun:
+		return logger.Green
+	case PlanStatusFail:
+		return logger.Red
+	case PlanStatusUpToDate:
+		return logger.Magenta
+	default:
+		return logger.Yellow
+	}
+}
+
+type planner struct {
+	e         *Executor
+	seen      map[string]bool
+	callCount map[string]int
+}
+
+func (p *planner) plan(ctx context.Context, call *Call) (*PlanNode, error) {
+	e := p.e
+
+	t, err := e.CompiledTask(call)
+	if err != nil {
+		return nil, err
+	}
+	node := &PlanNode{Task: t.Name()}
+
+	if !shouldRunOnCurrentPlatform(t.Platforms) {
+		node.Status = PlanStatusSkipped
+		node.Reason = "not for current platform"
+		return node, nil
+	}
+	if err := e.areTaskRequiredVarsSet(t); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(t.If) != "" && !p.conditionMet(ctx, t, t.If) {
+		node.Status = PlanStatusSkipped
+		node.Reason = "if condition not met"
+		return node, nil
+	}
+
+	if p.callCount[t.Task]++; p.callCount[t.Task] >= MaximumTaskCall {
+		return nil, &errors.TaskCalledTooManyTimesError{
+			TaskName:        t.Task,
+			MaximumTaskCall: MaximumTaskCall,
+		}
+	}
+
+	// Deduplicate tasks the same way as [Executor.startExecution] does
+	h, err := e.GetHash(t)
+	if err != nil {
+		return nil, err
+	}
+	if h != "" {
+		if p.seen[h] {
+			node.Status = PlanStatusDeduplicated
+			node.Reason = "already part of the plan"
+			return node, nil
+		}
+		p.seen[h] = true
+	}
+
+	for _, d := range t.Deps {
+		child, err := p.plan(ctx, &Call{Task: d.Task, Vars: d.Vars, Silent: d.Silent, Indirect: true})
+		if err != nil {
+			return nil, err
+		}
+		node.Deps = append(node.Deps, child)
+	}
+
+	node.Status, node.Reason, err = p.status(ctx, t, call)
+	if err != nil {
+		return nil, err
+	}
+	if node.Status != PlanStatusRun {
+		return node, nil
+	}
+
+	for _, cmd := range t.Cmds {
+		if strings.TrimSpace(cmd.If) != "" && !p.conditionMet(ctx, t, cmd.If) {
+			continue
+		}
+		switch {
+		case cmd.Task != "":
+			child, err := p.plan(ctx, &Call{Task: cmd.Task, Vars: cmd.Vars, Silent: cmd.Silent, Indirect: true})
+			if err != nil {
+				return nil, err
+			}
+			node.Calls = append(node.Calls, child)
+		case cmd.Cmd != "":
+			if shouldRunOnCurrentPlatform(cmd.Platforms) {
+				node.Cmds = append(node.Cmds, cmd.Cmd)
+			}
+		}
+	}
+	return node, nil
+}
+
+// status returns whether the commands of the given task would run. It uses
+// the same checks as [Executor.RunTask], but never updates any fingerprint.
+func (p *planner) status(ctx context.Context, t *ast.Task, call *Call) (PlanStatus, string, error) {
+	e := p.e
+
+	if e.ForceAll || (!call.Indirect && e.Force) {
+		return PlanStatusRun, "forced", nil
+	}
+
+	for _, precondition := range t.Preconditions {
+		if !p.conditionMet(ctx, t, precondition.Sh) {
+			return PlanStatusFail, precondition.Msg, nil
+		}
+	}
+
+	method := e.Taskfile.Method
+	if t.Method != "" {
+		method = t.Method
+	}
+	reason, err := fingerprint.WhyNotUpToDate(ctx, t,
+		fingerprint.WithMethod(method),
+		fingerprint.WithTempDir(e.TempDir.Fingerprint),
+		fingerprint.WithDry(true),
+		fingerprint.WithLogger(e.Logger),
+	)
+	if err != nil {
+		return "", "", err
+	}
+	if reason == nil {
+		return PlanStatusUpToDate, "", nil
+	}
+	return PlanStatusRun, reason.String(), nil
+}
+
+func (p *planner) conditionMet(ctx context.Context, t *ast.Task, command string) bool {
+	err := execext.RunCommand(ctx, &execext.RunCommandOptions{
+		Command: command,
+		Dir:     t.Dir,
+		Env:     env.Get(t),
+	})
+	return err == nil
+}
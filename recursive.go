@@ -0,0 +1,307 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/internal/summary"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// runRecursive runs the calls that must run in several namespaces in every
+// included namespace that defines the called task, and the other calls as
+// usual. The calls are run in the order they were given.
+func (e *Executor) runRecursive(ctx context.Context, calls ...*Call) error {
+	for len(calls) > 0 {
+		i := slices.IndexFunc(calls, e.shouldRunRecursive)
+		switch {
+		case i < 0:
+			return e.runCalls(ctx, calls...)
+		case i > 0:
+			if err := e.runCalls(ctx, calls[:i]...); err != nil {
+				return err
+			}
+			calls = calls[i:]
+		default:
+			if err := e.runRecursiveCall(ctx, calls[0]); err != nil {
+				return err
+			}
+			calls = calls[1:]
+		}
+	}
+	return nil
+}
+
+// runRecursiveCall runs the given call in every namespace that defines the
+// called task. The namespaces are run in the order of the dependencies between
+// them and the independent ones are run in parallel.
+func (e *Executor) runRecursiveCall(ctx context.Context, call *Call) error {
+	tasks, err := e.recursiveTasks(call)
+	if err != nil {
+		return err
+	}
+	calls := make(map[string]*Call, len(tasks))
+	for namespace, task := range tasks {
+		calls[namespace] = &Call{
+			Task:   task.Task,
+			Vars:   call.Vars.DeepCopy(),
+			Silent: call.Silent,
+		}
+	}
+	if e.AffectedSince != "" {
+		affectedCalls, err := e.filterAffectedCalls(sortedCalls(calls))
+		if err != nil {
+			return err
+		}
+		for namespace, c := range calls {
+			if !slices.Contains(affectedCalls, c) {
+				delete(tasks, namespace)
+				delete(calls, namespace)
+			}
+		}
+	}
+	deps := e.namespaceDeps(tasks)
+	if err := checkNamespaceCycles(deps); err != nil {
+		return err
+	}
+
+	namespaces := slices.Sorted(maps.Keys(tasks))
+	if err := e.promptDepsVars(sortedCalls(calls)); err != nil {
+		return err
+	}
+
+	if e.Summary {
+		for i, namespace := range namespaces {
+			compiledTask, err := e.FastCompiledTask(calls[namespace])
+			if err != nil {
+				return err
+			}
+			summary.PrintSpaceBetweenSummaries(e.Logger, i)
+			summary.PrintTask(e.Logger, compiledTask)
+		}
+		return nil
+	}
+
+	return e.withAllHooks(ctx, sortedCalls(calls), func(ctx context.Context) error {
+		return e.runNamespaces(ctx, namespaces, deps, calls)
+	})
+}
+
+// runNamespaces runs the calls of the given namespaces once the namespaces
+// they depend on have finished running.
+func (e *Executor) runNamespaces(ctx context.Context, namespaces []string, deps map[string][]string, calls map[string]*Call) error {
+	done := make(map[string]chan struct{}, len(namespaces))
+	failed := make(map[string]*atomic.Bool, len(namespaces))
+	for _, namespace := range namespaces {
+		done[namespace] = make(chan struct{})
+		failed[namespace] = &atomic.Bool{}
+	}
+
+	g := &errgroup.Group{}
+	if e.Failfast {
+		g, ctx = errgroup.WithContext(ctx)
+	}
+	for _, namespace := range namespaces {
+		g.Go(func() error {
+			defer close(done[namespace])
+			for _, dep := range deps[namespace] {
+				select {
+				case <-done[dep]:
+				case <-ctx.Done():
+					failed[namespace].Store(true)
+					return ctx.Err()
+				}
+				// Namespaces that depend on a failed namespace are skipped
+				if failed[dep].Load() {
+					failed[namespace].Store(true)
+					e.Logger.VerboseErrf(logger.Yellow, "task: %q skipped because %q failed\n", calls[namespace].Task, calls[dep].Task)
+					return nil
+				}
+			}
+			if err := e.RunTask(ctx, calls[namespace]); err != nil {
+				failed[namespace].Store(true)
+				return err
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// sortedCalls returns the calls sorted by namespace.
+func sortedCalls(calls map[string]*Call) []*Call {
+	sorted := make([]*Call, 0, len(calls))
+	for _, namespace := range slices.Sorted(maps.Keys(calls)) {
+		sorted = append(sorted, calls[namespace])
+	}
+	return sorted
+}
+
+// recursiveTasks returns the tasks matching the given call, keyed by their
+// namespace. When running recursively, these are the tasks with the same name
+// in every included namespace. Otherwise, the call is a pattern in which each
+// "*" matches a single namespace, like "packages:*:build".
+func (e *Executor) recursiveTasks(call *Call) (map[string]*ast.Task, error) {
+	tasks := make(map[string]*ast.Task)
+	for task := range e.Taskfile.Tasks.Values(nil) {
+		if task.Internal {
+			continue
+		}
+		var namespace string
+		var ok bool
+		if isNamespacePattern(call.Task) {
+			namespace, ok = matchNamespacePattern(call.Task, task.Task)
+		} else {
+			namespace, ok = strings.CutSuffix(task.Task, ast.NamespaceSeparator+call.Task)
+		}
+		if ok && namespace != "" {
+			tasks[namespace] = task
+		}
+	}
+	if len(tasks) == 0 {
+		return nil, &errors.TaskNotFoundError{TaskName: call.Task}
+	}
+	return tasks, nil
+}
+
+// namespaceDeps returns the namespaces each namespace depends on. A namespace
+// depends on another one when its task calls a task of the other namespace,
+// directly or through tasks that don't belong to any of the namespaces.
+func (e *Executor) namespaceDeps(tasks map[string]*ast.Task) map[string][]string {
+	owner := func(name string) string {
+		var longest string
+		for namespace := range tasks {
+			if strings.HasPrefix(name, namespace+ast.NamespaceSeparator) && len(namespace) > len(longest) {
+				longest = namespace
+			}
+		}
+		return longest
+	}
+
+	deps := make(map[string][]string, len(tasks))
+	for namespace, task := range tasks {
+		seen := map[string]bool{task.Task: true}
+		queue := []*ast.Task{task}
+		for len(queue) > 0 {
+			t := queue[0]
+			queue = queue[1:]
+			var called []string
+			for _, dep := range t.Deps {
+				if dep != nil && dep.Task != "" {
+					called = append(called, dep.Task)
+				}
+			}
+			for _, cmd := range t.Cmds {
+				if cmd != nil && cmd.Task != "" {
+					called = append(called, cmd.Task)
+				}
+			}
+			for _, name := range called {
+				if seen[name] {
+					continue
+				}
+				seen[name] = true
+				if other := owner(name); other != "" && other != namespace {
+					deps[namespace] = append(deps[namespace], other)
+					continue
+				}
+				if calledTask, ok := e.Taskfile.Tasks.Get(name); ok {
+					queue = append(queue, calledTask)
+				}
+			}
+		}
+		slices.Sort(deps[namespace])
+		deps[namespace] = slices.Compact(deps[namespace])
+	}
+	return deps
+}
+
+// checkNamespaceCycles returns an error if the dependencies between the
+// namespaces contain a cycle, which would never finish running.
+func checkNamespaceCycles(deps map[string][]string) error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(deps))
+	var visit func(namespace string) error
+	visit = func(namespace string) error {
+		state[namespace] = visiting
+		for _, dep := range deps[namespace] {
+			switch state[dep] {
+			case visiting:
+				return fmt.Errorf("task: Dependency cycle detected between namespaces %q and %q", namespace, dep)
+			case 0:
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+		state[namespace] = visited
+		return nil
+	}
+	for _, namespace := range slices.Sorted(maps.Keys(deps)) {
+		if state[namespace] == 0 {
+			if err := visit(namespace); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// shouldRunRecursive returns true if the given call must be run in several
+// namespaces, either because the executor runs recursively or because the
+// call is a pattern of namespaces that doesn't match any task by itself.
+func (e *Executor) shouldRunRecursive(call *Call) bool {
+	if e.Recursive {
+		return true
+	}
+	if !isNamespacePattern(call.Task) {
+		return false
+	}
+	matchingTasks, err := e.FindMatchingTasks(call)
+	return err == nil && len(matchingTasks) == 0
+}
+
+// isNamespacePattern returns true if the namespace of the given name contains
+// a "*", but not the name of the task itself.
+func isNamespacePattern(name string) bool {
+	namespace, taskName, ok := cutLastNamespace(name)
+	return ok && strings.Contains(namespace, "*") && !strings.Contains(taskName, "*")
+}
+
+func cutLastNamespace(name string) (string, string, bool) {
+	i := strings.LastIndex(name, ast.NamespaceSeparator)
+	if i < 0 {
+		return "", name, false
+	}
+	return name[:i], name[i+1:], true
+}
+
+// matchNamespacePattern returns the namespace of the given task if its name
+// matches the pattern. Each segment of the pattern matches a single segment
+// of the name.
+func matchNamespacePattern(pattern, name string) (string, bool) {
+	patternSegments := strings.Split(pattern, ast.NamespaceSeparator)
+	nameSegments := strings.Split(name, ast.NamespaceSeparator)
+	if len(patternSegments) != len(nameSegments) {
+		return "", false
+	}
+	for i := range patternSegments {
+		if ok, _ := path.Match(patternSegments[i], nameSegments[i]); !ok {
+			return "", false
+		}
+	}
+	namespace, _, _ := cutLastNamespace(name)
+	return namespace, true
+}
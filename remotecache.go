@@ -0,0 +1,92 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ladicle/tabwriter"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile"
+)
+
+// SetupRemoteCache only sets up what is needed to manage the cache of remote
+// Taskfiles, without reading the Taskfiles, which could download them.
+func (e *Executor) SetupRemoteCache() error {
+	e.setupLogger()
+	if _, err := e.getRootNode(); err != nil {
+		return err
+	}
+	return e.setupTempDir()
+}
+
+// ListRemoteCache prints the remote Taskfiles, the git repositories and the
+// archives in the cache.
+func (e *Executor) ListRemoteCache(ctx context.Context) error {
+	entries, err := taskfile.ListCache(ctx, e.TempDir.Remote)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		e.Logger.Outf(logger.Yellow, "task: The remote cache is empty\n")
+		return nil
+	}
+	return e.printRemoteCache(entries)
+}
+
+// PruneRemoteCache removes the remote Taskfiles, the git repositories and the
+// archives that were downloaded before the cache expiry duration, and prints
+// them.
+func (e *Executor) PruneRemoteCache(ctx context.Context) error {
+	if e.CacheExpiryDuration <= 0 {
+		return errors.New("task: Pruning the remote cache requires a cache expiry, set with --expiry or remote.cache-expiry")
+	}
+	pruned, err := taskfile.PruneCache(ctx, e.TempDir.Remote, e.CacheExpiryDuration)
+	if err != nil {
+		return err
+	}
+	if len(pruned) == 0 {
+		e.Logger.Outf(logger.Yellow, "task: No entries older than %s in the remote cache\n", e.CacheExpiryDuration)
+		return nil
+	}
+	e.Logger.Outf(logger.Default, "task: Pruned %d entries from the remote cache:\n", len(pruned))
+	return e.printRemoteCache(pruned)
+}
+
+// ClearRemoteCache removes every remote Taskfile, git repository and archive
+// from the cache.
+func (e *Executor) ClearRemoteCache() error {
+	return taskfile.ClearCache(e.TempDir.Remote)
+}
+
+func (e *Executor) printRemoteCache(entries []*taskfile.CacheEntry) error {
+	w := tabwriter.NewWriter(e.Stdout, 0, 8, 2, ' ', 0)
+	e.Logger.FOutf(w, logger.Default, "KIND\tURL\tAGE\tSIZE\tCHECKSUM\n")
+	for _, entry := range entries {
+		url := entry.URL
+		if url == "" {
+			url = entry.Path
+		}
+		age := time.Since(entry.Timestamp).Round(time.Second)
+		e.Logger.FOutf(w, logger.Yellow, "%s\t", entry.Kind)
+		e.Logger.FOutf(w, logger.Green, "%s\t", url)
+		e.Logger.FOutf(w, logger.Default, "%s\t%s\t%s\n", age, formatSize(entry.Size), entry.Checksum)
+	}
+	return w.Flush()
+}
+
+// formatSize formats a size in bytes using binary units.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
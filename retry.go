@@ -0,0 +1,58 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"mvdan.cc/sh/v3/interp"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/logger"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// withAttempt returns a copy of the call for the given retry attempt.
+func (c *Call) withAttempt(attempt int) *Call {
+	retryCall := *c
+	retryCall.Attempt = attempt
+	return &retryCall
+}
+
+// shouldRetry returns true if the given error is eligible for another attempt.
+// Only errors caused by a non-zero exit code are retried. Cancellations and
+// errors raised by Task itself (missing tasks, template errors, etc.) are
+// returned immediately.
+func (e *Executor) shouldRetry(ctx context.Context, r *ast.Retries, attempt int, err error) bool {
+	if r == nil || err == nil || e.Dry || attempt > r.Count || ctx.Err() != nil {
+		return false
+	}
+	var exitCode interp.ExitStatus
+	if !errors.As(err, &exitCode) {
+		return false
+	}
+	return r.ShouldRetryExitCode(int(exitCode))
+}
+
+// waitForRetry logs a failed attempt and blocks until the next attempt should
+// start or the context is cancelled.
+func (e *Executor) waitForRetry(ctx context.Context, name string, r *ast.Retries, attempt int, err error) error {
+	delay := r.DelayFor(attempt)
+	if delay > 0 {
+		e.Logger.Errf(logger.Yellow, "task: [%s] attempt %d/%d failed: %v - retrying in %s\n", name, attempt, r.Count+1, err, delay)
+	} else {
+		e.Logger.Errf(logger.Yellow, "task: [%s] attempt %d/%d failed: %v - retrying\n", name, attempt, r.Count+1, err)
+	}
+
+	// Release our execution slot while we wait, so that other tasks can run
+	reacquire := e.releaseConcurrencyLimit()
+	defer reacquire()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/sajari/fuzzy"
 
 	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/buildcache"
 	"github.com/go-task/task/v3/internal/env"
 	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/filepathext"
@@ -32,6 +33,9 @@ func (e *Executor) Setup() error {
 	if err := e.setupTempDir(); err != nil {
 		return err
 	}
+	if err := e.setupBuildCache(); err != nil {
+		return err
+	}
 	if err := e.readTaskfile(node); err != nil {
 		return err
 	}
@@ -58,6 +62,8 @@ func (e *Executor) getRootNode() (taskfile.Node, error) {
 		taskfile.WithCACert(e.CACert),
 		taskfile.WithCert(e.Cert),
 		taskfile.WithCertKey(e.CertKey),
+		taskfile.WithMirrors(e.Mirrors),
+		taskfile.WithCredentials(e.Credentials),
 	)
 	var taskNotFoundError errors.TaskfileNotFoundError
 	if errors.As(err, &taskNotFoundError) {
@@ -81,18 +87,32 @@ func (e *Executor) readTaskfile(node taskfile.Node) error {
 	promptFunc := func(s string) error {
 		return e.Logger.Prompt(logger.Yellow, s, "n", "y", "yes")
 	}
+	lockMode := taskfile.LockModeVerify
+	switch {
+	case e.UpdateLock:
+		lockMode = taskfile.LockModeUpdate
+	case e.Lock:
+		lockMode = taskfile.LockModeWrite
+	}
 	reader := taskfile.NewReader(
 		taskfile.WithInsecure(e.Insecure),
 		taskfile.WithDownload(e.Download),
 		taskfile.WithOffline(e.Offline),
 		taskfile.WithTrustedHosts(e.TrustedHosts),
+		taskfile.WithTrustedKeys(e.TrustedKeys),
 		taskfile.WithTempDir(e.TempDir.Remote),
 		taskfile.WithCacheExpiryDuration(e.CacheExpiryDuration),
 		taskfile.WithReaderCACert(e.CACert),
 		taskfile.WithReaderCert(e.Cert),
 		taskfile.WithReaderCertKey(e.CertKey),
+		taskfile.WithReaderMirrors(e.Mirrors),
+		taskfile.WithReaderCredentials(e.Credentials),
 		taskfile.WithDebugFunc(debugFunc),
 		taskfile.WithPromptFunc(promptFunc),
+		taskfile.WithLockfile(filepath.Join(e.rootDir(node), taskfile.LockfileName)),
+		taskfile.WithLockMode(lockMode),
+		taskfile.WithVendorDir(filepath.Join(e.rootDir(node), taskfile.VendorDir)),
+		taskfile.WithVendor(e.Vendor),
 	)
 	graph, err := reader.Read(ctx, node)
 	if err != nil {
@@ -107,6 +127,16 @@ func (e *Executor) readTaskfile(node taskfile.Node) error {
 	return nil
 }
 
+// rootDir returns the directory of the root Taskfile, where the lock file and
+// the vendored Taskfiles are stored. If the root Taskfile is not local, the
+// root directory is used instead.
+func (e *Executor) rootDir(node taskfile.Node) string {
+	if _, ok := node.(*taskfile.FileNode); ok {
+		return filepath.Dir(node.Location())
+	}
+	return e.Dir
+}
+
 func (e *Executor) setupFuzzyModel() {
 	if e.Taskfile == nil {
 		return
@@ -174,6 +204,24 @@ func (e *Executor) setupTempDir() error {
 	return nil
 }
 
+func (e *Executor) setupBuildCache() error {
+	dir := filepathext.SmartJoin(e.TempDir.Fingerprint, "cache")
+	if e.BuildCacheDir != "" {
+		var err error
+		if dir, err = execext.ExpandLiteral(e.BuildCacheDir); err != nil {
+			return err
+		}
+		dir = filepathext.SmartJoin(e.Dir, dir)
+	}
+
+	stores := []buildcache.Store{buildcache.NewDirStore(dir)}
+	if e.BuildCacheURL != "" {
+		stores = append(stores, buildcache.NewHTTPStore(e.BuildCacheURL, e.BuildCacheToken))
+	}
+	e.buildCache = buildcache.New(stores...)
+	return nil
+}
+
 func (e *Executor) setupStdFiles() {
 	if e.Stdin == nil {
 		e.Stdin = os.Stdin
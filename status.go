@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/go-task/task/v3/internal/fingerprint"
+	"github.com/go-task/task/v3/internal/logger"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
@@ -41,6 +42,61 @@ func (e *Executor) Status(ctx context.Context, calls ...*Call) error {
 	return nil
 }
 
+// Why prints whether each of the given tasks is up-to-date and, if not, the
+// reason why it would run.
+func (e *Executor) Why(ctx context.Context, calls ...*Call) error {
+	for _, call := range calls {
+
+		// Compile the task
+		t, err := e.CompiledTask(call)
+		if err != nil {
+			return err
+		}
+
+		// Get the fingerprinting method to use
+		method := e.Taskfile.Method
+		if t.Method != "" {
+			method = t.Method
+		}
+
+		// Never update the fingerprint of the task
+		reason, err := fingerprint.WhyNotUpToDate(ctx, t,
+			fingerprint.WithMethod(method),
+			fingerprint.WithTempDir(e.TempDir.Fingerprint),
+			fingerprint.WithDry(true),
+			fingerprint.WithLogger(e.Logger),
+		)
+		if err != nil {
+			return err
+		}
+		if reason == nil {
+			e.Logger.Outf(logger.Green, "task: Task %q is up-to-date\n", t.Name())
+			continue
+		}
+		var what string
+		switch reason.Kind {
+		case fingerprint.ReasonSourcesChanged:
+			what = "sources changed"
+		case fingerprint.ReasonGeneratesChanged:
+			what = "generated files changed"
+		default:
+			e.Logger.Outf(logger.Yellow, "task: Task %q is not up-to-date: %s\n", t.Name(), reason)
+			continue
+		}
+		e.Logger.Outf(logger.Yellow, "task: Task %q is not up-to-date: %s\n", t.Name(), what)
+		for _, f := range reason.Changed {
+			e.Logger.Outf(logger.Default, "  changed: %s\n", f)
+		}
+		for _, f := range reason.Added {
+			e.Logger.Outf(logger.Green, "  added:   %s\n", f)
+		}
+		for _, f := range reason.Removed {
+			e.Logger.Outf(logger.Red, "  removed: %s\n", f)
+		}
+	}
+	return nil
+}
+
 func (e *Executor) statusOnError(t *ast.Task) error {
 	method := t.Method
 	if method == "" {
@@ -52,3 +108,15 @@ func (e *Executor) statusOnError(t *ast.Task) error {
 	}
 	return checker.OnError(t)
 }
+
+func (e *Executor) statusOnSuccess(t *ast.Task) error {
+	method := t.Method
+	if method == "" {
+		method = e.Taskfile.Method
+	}
+	checker, err := fingerprint.NewSourcesChecker(method, e.TempDir.Fingerprint, e.Dry)
+	if err != nil {
+		return err
+	}
+	return checker.OnSuccess(t)
+}
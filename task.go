@@ -1,6 +1,7 @@
 package task
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
@@ -42,6 +43,9 @@ type MatchingTask struct {
 func (e *Executor) Run(ctx context.Context, calls ...*Call) error {
 	// check if given tasks exist
 	for _, call := range calls {
+		if e.shouldRunRecursive(call) {
+			continue
+		}
 		task, err := e.GetTask(call)
 		if err != nil {
 			if _, ok := err.(*errors.TaskNotFoundError); ok {
@@ -62,6 +66,21 @@ func (e *Executor) Run(ctx context.Context, calls ...*Call) error {
 		}
 	}
 
+	if slices.ContainsFunc(calls, e.shouldRunRecursive) {
+		return e.runRecursive(ctx, calls...)
+	}
+	return e.runCalls(ctx, calls...)
+}
+
+// runCalls runs the given calls, which must exist.
+func (e *Executor) runCalls(ctx context.Context, calls ...*Call) error {
+	if e.AffectedSince != "" {
+		var err error
+		if calls, err = e.filterAffectedCalls(calls); err != nil {
+			return err
+		}
+	}
+
 	if e.Summary {
 		for i, c := range calls {
 			compiledTask, err := e.FastCompiledTask(c)
@@ -84,20 +103,22 @@ func (e *Executor) Run(ctx context.Context, calls ...*Call) error {
 		return err
 	}
 
-	g := &errgroup.Group{}
-	if e.Failfast {
-		g, ctx = errgroup.WithContext(ctx)
-	}
-	for _, c := range regularCalls {
-		if e.Parallel {
-			g.Go(func() error { return e.RunTask(ctx, c) })
-		} else {
-			if err := e.RunTask(ctx, c); err != nil {
-				return err
+	if err := e.withAllHooks(ctx, regularCalls, func(ctx context.Context) error {
+		g := &errgroup.Group{}
+		if e.Failfast {
+			g, ctx = errgroup.WithContext(ctx)
+		}
+		for _, c := range regularCalls {
+			if e.Parallel {
+				g.Go(func() error { return e.RunTask(ctx, c) })
+			} else {
+				if err := e.RunTask(ctx, c); err != nil {
+					return err
+				}
 			}
 		}
-	}
-	if err := g.Wait(); err != nil {
+		return g.Wait()
+	}); err != nil {
 		return err
 	}
 
@@ -204,7 +225,7 @@ func (e *Executor) RunTask(ctx context.Context, call *Call) error {
 	release := e.acquireConcurrencyLimit()
 	defer release()
 
-	if err = e.startExecution(ctx, t, func(ctx context.Context) error {
+	if err = e.startExecution(ctx, t, e.withEachHooks(t, func(ctx context.Context) error {
 		e.Logger.VerboseErrf(logger.Magenta, "task: %q started\n", call.Task)
 		if err := e.runDeps(ctx, t); err != nil {
 			return err
@@ -226,7 +247,7 @@ func (e *Executor) RunTask(ctx context.Context, call *Call) error {
 			if t.Method != "" {
 				method = t.Method
 			}
-			upToDate, err := fingerprint.IsTaskUpToDate(ctx, t,
+			reason, err := fingerprint.WhyNotUpToDate(ctx, t,
 				fingerprint.WithMethod(method),
 				fingerprint.WithTempDir(e.TempDir.Fingerprint),
 				fingerprint.WithDry(e.Dry),
@@ -235,8 +256,11 @@ func (e *Executor) RunTask(ctx context.Context, call *Call) error {
 			if err != nil {
 				return err
 			}
+			if reason != nil && reason.Kind != fingerprint.ReasonNoSourcesOrStatus {
+				e.Logger.VerboseErrf(logger.Magenta, "task: Task %q is not up-to-date: %s\n", t.Name(), reason)
+			}
 
-			if upToDate && preCondMet {
+			if reason == nil && preCondMet {
 				if e.Verbose || (!call.Silent && !t.IsSilent() && !e.Taskfile.Silent && !e.Silent) {
 					name := t.Name()
 					if e.OutputStyle.Name == "prefixed" {
@@ -248,6 +272,16 @@ func (e *Executor) RunTask(ctx context.Context, call *Call) error {
 			}
 		}
 
+		// The key is computed before running the commands, as they might
+		// change the sources
+		cacheKey := e.buildCacheKey(t, call)
+		if !skipFingerprinting && e.restoreFromBuildCache(ctx, t, call, cacheKey) {
+			if err := e.statusOnSuccess(t); err != nil {
+				e.Logger.VerboseErrf(logger.Yellow, "task: error updating status on success: %v\n", err)
+			}
+			return nil
+		}
+
 		for _, p := range t.Prompt {
 			if p != "" && !e.Dry {
 				if err := e.Logger.Prompt(logger.Yellow, p, "n", "y", "yes"); errors.Is(err, logger.ErrNoTerminal) {
@@ -264,34 +298,74 @@ func (e *Executor) RunTask(ctx context.Context, call *Call) error {
 			e.Logger.Errf(logger.Red, "task: cannot make directory %q: %v\n", t.Dir, err)
 		}
 
-		var deferredExitCode uint8
+		// The task timeout covers all of its commands, including retries, but
+		// not its dependencies or deferred commands
+		cmdsCtx, cancel := withTimeout(ctx, cmp.Or(t.Timeout, e.TaskTimeout), &errors.TaskTimeoutError{TaskName: t.Name()})
+		defer cancel()
 
-		for i := range t.Cmds {
-			if t.Cmds[i].Defer {
-				defer e.runDeferred(t, call, i, t.Vars, &deferredExitCode)
-				continue
+		// Deferred commands are only registered once, even if the task is
+		// retried, and run after the final attempt, with the task compiled
+		// for it
+		var failure deferredFailure
+		var deferred []int
+		defer func() {
+			for _, i := range slices.Backward(deferred) {
+				e.runDeferred(t, call, i, t.Vars, &failure)
 			}
-
-			if err := e.runCommand(ctx, t, call, i); err != nil {
-				if err2 := e.statusOnError(t); err2 != nil {
-					e.Logger.VerboseErrf(logger.Yellow, "task: error cleaning status on error: %v\n", err2)
+		}()
+
+	attempts:
+		for attempt := 1; ; attempt++ {
+			for i := range t.Cmds {
+				if t.Cmds[i].Defer {
+					if !slices.Contains(deferred, i) {
+						deferred = append(deferred, i)
+					}
+					continue
 				}
 
-				var exitCode interp.ExitStatus
-				if errors.As(err, &exitCode) {
-					if t.IgnoreError {
-						e.Logger.VerboseErrf(logger.Yellow, "task: task error ignored: %v\n", err)
-						continue
+				if err := e.runCommand(cmdsCtx, t, call, i); err != nil {
+					err = timeoutError(cmdsCtx, err)
+					if err2 := e.statusOnError(t); err2 != nil {
+						e.Logger.VerboseErrf(logger.Yellow, "task: error cleaning status on error: %v\n", err2)
 					}
-					deferredExitCode = uint8(exitCode)
-				}
 
-				return err
+					var exitCode interp.ExitStatus
+					if errors.As(err, &exitCode) {
+						if t.IgnoreError {
+							e.Logger.VerboseErrf(logger.Yellow, "task: task error ignored: %v\n", err)
+							continue
+						}
+						failure.exitCode = uint8(exitCode)
+					}
+					failure.cmd = cmdName(t.Cmds[i])
+					failure.err = err
+
+					if !e.shouldRetry(cmdsCtx, t.Retries, attempt, err) {
+						return err
+					}
+					if err := e.waitForRetry(cmdsCtx, t.Name(), t.Retries, attempt, err); err != nil {
+						return timeoutError(cmdsCtx, err)
+					}
+
+					// Recompile the task so that TASK_ATTEMPT is up-to-date
+					call = call.withAttempt(attempt + 1)
+					if t, err = e.CompiledTask(call); err != nil {
+						return err
+					}
+					failure = deferredFailure{}
+					continue attempts
+				}
 			}
+			break
 		}
+		if err := e.statusOnSuccess(t); err != nil {
+			e.Logger.VerboseErrf(logger.Yellow, "task: error updating status on success: %v\n", err)
+		}
+		e.saveToBuildCache(ctx, t, cacheKey)
 		e.Logger.VerboseErrf(logger.Magenta, "task: %q finished\n", call.Task)
 		return nil
-	}); err != nil {
+	})); err != nil {
 		return &errors.TaskRunError{TaskName: t.Name(), Err: err}
 	}
 
@@ -337,16 +411,34 @@ func (e *Executor) runDeps(ctx context.Context, t *ast.Task) error {
 	return g.Wait()
 }
 
-func (e *Executor) runDeferred(t *ast.Task, call *Call, i int, vars *ast.Vars, deferredExitCode *uint8) {
+// deferredFailure is the failure of the command that made a task fail, which
+// is exposed to its deferred commands.
+type deferredFailure struct {
+	exitCode uint8
+	cmd      string
+	err      error
+}
+
+func (e *Executor) runDeferred(t *ast.Task, call *Call, i int, vars *ast.Vars, failure *deferredFailure) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	cmd := t.Cmds[i]
+	failed := failure != nil && failure.err != nil
+	if (cmd.DeferOn == ast.DeferOnFailure && !failed) || (cmd.DeferOn == ast.DeferOnSuccess && failed) {
+		e.Logger.VerboseErrf(logger.Yellow, "task: [%s] deferred cmd only runs on %s - skipped\n", t.Name(), cmd.DeferOn)
+		return
+	}
+
 	cache := &templater.Cache{Vars: vars}
 	extra := map[string]any{}
 
-	if deferredExitCode != nil && *deferredExitCode > 0 {
-		extra["EXIT_CODE"] = fmt.Sprintf("%d", *deferredExitCode)
+	if failed {
+		if failure.exitCode > 0 {
+			extra["EXIT_CODE"] = fmt.Sprintf("%d", failure.exitCode)
+		}
+		extra["FAILED_CMD"] = failure.cmd
+		extra["ERROR"] = failure.err.Error()
 	}
 
 	cmd.Cmd = templater.ReplaceWithExtra(cmd.Cmd, cache, extra)
@@ -362,6 +454,42 @@ func (e *Executor) runDeferred(t *ast.Task, call *Call, i int, vars *ast.Vars, d
 func (e *Executor) runCommand(ctx context.Context, t *ast.Task, call *Call, i int) error {
 	cmd := t.Cmds[i]
 
+	for attempt := 1; ; attempt++ {
+		err := e.runCommandAttempt(ctx, t, call, i, attempt)
+		if err == nil {
+			return nil
+		}
+		if !cmd.Defer && e.shouldRetry(ctx, cmd.Retries, attempt, err) {
+			if err := e.waitForRetry(ctx, t.Name(), cmd.Retries, attempt, err); err != nil {
+				return err
+			}
+
+			// Recompile the task so that TASK_ATTEMPT is up-to-date
+			if t, err = e.CompiledTask(call.withAttempt(attempt + 1)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var exitCode interp.ExitStatus
+		if errors.As(err, &exitCode) && cmd.IgnoreError {
+			if cmd.Task != "" {
+				e.Logger.VerboseErrf(logger.Yellow, "task: [%s] task error ignored: %v\n", t.Name(), err)
+			} else {
+				e.Logger.VerboseErrf(logger.Yellow, "task: [%s] command error ignored: %v\n", t.Name(), err)
+			}
+			return nil
+		}
+		return err
+	}
+}
+
+func (e *Executor) runCommandAttempt(ctx context.Context, t *ast.Task, call *Call, i int, attempt int) error {
+	cmd := t.Cmds[i]
+
+	ctx, cancel := withTimeout(ctx, cmd.Timeout, &errors.TaskTimeoutError{TaskName: t.Name(), Cmd: cmdName(cmd)})
+	defer cancel()
+
 	// Check if condition for any command type
 	if strings.TrimSpace(cmd.If) != "" {
 		if err := execext.RunCommand(ctx, &execext.RunCommandOptions{
@@ -379,13 +507,8 @@ func (e *Executor) runCommand(ctx context.Context, t *ast.Task, call *Call, i in
 		reacquire := e.releaseConcurrencyLimit()
 		defer reacquire()
 
-		err := e.RunTask(ctx, &Call{Task: cmd.Task, Vars: cmd.Vars, Silent: cmd.Silent, Indirect: true})
-		var exitCode interp.ExitStatus
-		if errors.As(err, &exitCode) && cmd.IgnoreError {
-			e.Logger.VerboseErrf(logger.Yellow, "task: [%s] task error ignored: %v\n", t.Name(), err)
-			return nil
-		}
-		return err
+		err := e.RunTask(ctx, &Call{Task: cmd.Task, Vars: cmd.Vars, Silent: cmd.Silent, Indirect: true, Attempt: attempt})
+		return timeoutError(ctx, err)
 	case cmd.Cmd != "":
 		if !shouldRunOnCurrentPlatform(cmd.Platforms) {
 			e.Logger.VerboseOutf(logger.Yellow, "task: [%s] %s not for current platform - ignored\n", t.Name(), cmd.Cmd)
@@ -421,15 +544,13 @@ func (e *Executor) runCommand(ctx context.Context, t *ast.Task, call *Call, i in
 			Stdin:     e.Stdin,
 			Stdout:    stdOut,
 			Stderr:    stdErr,
+			// Make sure no orphaned processes are left behind on timeouts
+			KillProcessTree: hasDeadline(ctx),
 		})
+		err = timeoutError(ctx, err)
 		if closeErr := closer(err); closeErr != nil {
 			e.Logger.Errf(logger.Red, "task: unable to close writer: %v\n", closeErr)
 		}
-		var exitCode interp.ExitStatus
-		if errors.As(err, &exitCode) && cmd.IgnoreError {
-			e.Logger.VerboseErrf(logger.Yellow, "task: [%s] command error ignored: %v\n", t.Name(), err)
-			return nil
-		}
 		return err
 	default:
 		return nil
@@ -1,16 +1,22 @@
 package task_test
 
 import (
+	"archive/zip"
 	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"io/fs"
 	"maps"
 	rand "math/rand/v2"
 	"net/http"
+	"net/http/cgi"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
@@ -25,11 +31,13 @@ import (
 	"github.com/sebdah/goldie/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/go-task/task/v3"
 	"github.com/go-task/task/v3/errors"
 	"github.com/go-task/task/v3/experiments"
 	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/taskfile"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
 
@@ -487,6 +495,281 @@ func TestStatusChecksum(t *testing.T) { // nolint:paralleltest // cannot run in
 	}
 }
 
+func TestWhy(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/why"
+
+	for _, f := range []string{".task", "src", "out.txt"} {
+		require.NoError(t, os.RemoveAll(filepathext.SmartJoin(dir, f)))
+	}
+	require.NoError(t, os.MkdirAll(filepathext.SmartJoin(dir, "src"), 0o755))
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, name), []byte(content), 0o644))
+	}
+	writeFile("src/a.txt", "a")
+	writeFile("src/b.txt", "b")
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+	)
+	require.NoError(t, e.Setup())
+
+	why := func() string {
+		buff.Reset()
+		require.NoError(t, e.Why(t.Context(), &task.Call{Task: "build"}))
+		return buff.String()
+	}
+
+	assert.Equal(t, "task: Task \"build\" is not up-to-date: no fingerprint of a previous run\n", why())
+	assert.Equal(t, "task: Task \"build\" is not up-to-date: no fingerprint of a previous run\n", why(), "--why should not update the fingerprint")
+
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "build"}))
+	assert.Equal(t, "task: Task \"build\" is up-to-date\n", why())
+
+	writeFile("src/a.txt", "changed")
+	writeFile("src/c.txt", "c")
+	require.NoError(t, os.Remove(filepathext.SmartJoin(dir, "src/b.txt")))
+	assert.Equal(t, `task: Task "build" is not up-to-date: sources changed
+  changed: src/a.txt
+  added:   src/c.txt
+  removed: src/b.txt
+`, why())
+
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "build"}))
+	require.NoError(t, os.Remove(filepathext.SmartJoin(dir, "out.txt")))
+	assert.Equal(t, "task: Task \"build\" is not up-to-date: generates \"./out.txt\" has no matching files\n", why())
+
+	buff.Reset()
+	require.NoError(t, e.Why(t.Context(), &task.Call{Task: "status"}))
+	assert.Equal(t, "task: Task \"status\" is not up-to-date: status command \"test -f ./missing.txt\" failed\n", buff.String())
+}
+
+func TestBuildCache(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/build_cache"
+
+	var mu sync.Mutex
+	entries := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := entries[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodPut:
+			entries[r.URL.Path], _ = io.ReadAll(r.Body)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, name), []byte(content), 0o644))
+	}
+	readFile := func(name string) string {
+		data, err := os.ReadFile(filepathext.SmartJoin(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+	// freshCheckout removes all local state, like on a new CI runner
+	freshCheckout := func() {
+		for _, f := range []string{".task", "out"} {
+			require.NoError(t, os.RemoveAll(filepathext.SmartJoin(dir, f)))
+		}
+	}
+	run := func(opts ...task.ExecutorOption) string {
+		var buff bytes.Buffer
+		e := task.NewExecutor(slices.Concat([]task.ExecutorOption{
+			task.WithDir(dir),
+			task.WithStdout(&buff),
+			task.WithStderr(&buff),
+			task.WithBuildCacheDir(t.TempDir()),
+			task.WithBuildCacheURL(server.URL),
+		}, opts)...)
+		require.NoError(t, e.Setup())
+		require.NoError(t, e.Run(t.Context(), &task.Call{Task: "build"}))
+		return buff.String()
+	}
+
+	freshCheckout()
+	require.NoError(t, os.RemoveAll(filepathext.SmartJoin(dir, "src")))
+	require.NoError(t, os.MkdirAll(filepathext.SmartJoin(dir, "src"), 0o755))
+	writeFile("src/a.txt", "a\n")
+
+	assert.Contains(t, run(), "building")
+	assert.Len(t, entries, 1)
+
+	freshCheckout()
+	output := run()
+	assert.NotContains(t, output, "building")
+	assert.Contains(t, output, `task: Task "build" restored from the build cache`)
+	assert.Equal(t, "hello\na\n", readFile("out/result.txt"))
+
+	writeFile("src/a.txt", "b\n")
+	freshCheckout()
+	assert.Contains(t, run(), "building")
+	assert.Equal(t, "hello\nb\n", readFile("out/result.txt"))
+	assert.Len(t, entries, 2)
+
+	freshCheckout()
+	output = run(task.WithForce(true))
+	assert.Contains(t, output, "building", "--force should not restore from the build cache")
+	assert.NotContains(t, output, "restored")
+}
+
+func TestBuildCacheCheckouts(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	// checkout creates a project in a new directory, with the source in the
+	// given subdirectory, and runs its build task
+	checkout := func(subdir string) string {
+		dir := t.TempDir()
+		for name, content := range map[string]string{
+			"Taskfile.yml": `version: '3'
+
+tasks:
+  build:
+    cache: true
+    sources: ['src/**/*.txt']
+    generates: [out/result.txt]
+    cmds:
+      - echo building
+      - mkdir -p '{{.ROOT_DIR}}/out'
+      - cat src/*/*.txt > '{{.ROOT_DIR}}/out/result.txt'
+`,
+			filepath.Join("src", subdir, "a.txt"): "a\n",
+		} {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+		}
+		var buff bytes.Buffer
+		e := task.NewExecutor(
+			task.WithDir(dir),
+			task.WithStdout(&buff),
+			task.WithStderr(&buff),
+			task.WithBuildCacheDir(cacheDir),
+		)
+		require.NoError(t, e.Setup())
+		require.NoError(t, e.Run(t.Context(), &task.Call{Task: "build"}))
+		return buff.String()
+	}
+
+	assert.Contains(t, checkout("x"), "building")
+	assert.Contains(t, checkout("x"), "restored from the build cache")
+	// Moving a source to another directory changes the key
+	assert.Contains(t, checkout("y"), "building")
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/fingerprint"
+
+	for _, f := range []string{".task", "out"} {
+		require.NoError(t, os.RemoveAll(filepathext.SmartJoin(dir, f)))
+	}
+	require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, "src.txt"), []byte("src"), 0o644))
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+	)
+	require.NoError(t, e.Setup())
+
+	run := func(name string, vars map[string]string) string {
+		call := &task.Call{Task: name, Vars: ast.NewVars()}
+		for k, v := range vars {
+			call.Vars.Set(k, ast.Var{Value: v})
+		}
+		buff.Reset()
+		require.NoError(t, e.Run(t.Context(), call))
+		return buff.String()
+	}
+
+	assert.Equal(t, "building linux 0\n", run("build", nil))
+	assert.Empty(t, run("build", nil))
+	assert.Equal(t, "building darwin 0\n", run("build", map[string]string{"GOOS": "darwin"}))
+	assert.Equal(t, "building darwin 1\n", run("build", map[string]string{"GOOS": "darwin", "CGO_ENABLED": "1"}))
+
+	// Switching back to a previous variant doesn't run the task again
+	assert.Empty(t, run("build", nil))
+	assert.Empty(t, run("build", map[string]string{"GOOS": "darwin"}))
+
+	assert.Equal(t, "hello\n", run("greet", nil))
+	assert.Empty(t, run("greet", nil))
+	assert.Equal(t, "hi\n", run("greet", map[string]string{"GREETING": "hi"}))
+	assert.Empty(t, run("greet", nil))
+
+	assert.Equal(t, "generating\n", run("codegen", nil))
+	assert.Empty(t, run("codegen", nil))
+	require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, "out/gen.txt"), []byte("edited"), 0o644))
+	buff.Reset()
+	require.NoError(t, e.Why(t.Context(), &task.Call{Task: "codegen"}))
+	assert.Equal(t, "task: Task \"codegen\" is not up-to-date: generated files changed\n  changed: out/gen.txt\n", buff.String())
+	assert.Equal(t, "generating\n", run("codegen", nil))
+	assert.Empty(t, run("codegen", nil))
+
+	// The timestamp method can't tell the variants apart
+	err := e.Run(t.Context(), &task.Call{Task: "timestamp"})
+	assert.ErrorContains(t, err, `can't use "fingerprint" with the "timestamp" method`)
+}
+
+func TestIgnoreFiles(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/ignore_files"
+
+	cleanup := func() {
+		for _, f := range []string{".task", "tmp", "src/debug.log"} {
+			require.NoError(t, os.RemoveAll(filepathext.SmartJoin(dir, f)))
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+	require.NoError(t, os.MkdirAll(filepathext.SmartJoin(dir, "tmp"), 0o755))
+	require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, "tmp/scratch.txt"), []byte("scratch"), 0o644))
+	require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, "src/debug.log"), []byte("debug"), 0o644))
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+	)
+	require.NoError(t, e.Setup())
+
+	run := func(name string) string {
+		buff.Reset()
+		require.NoError(t, e.Run(t.Context(), &task.Call{Task: name}))
+		return buff.String()
+	}
+
+	// Files ignored by .gitignore and .taskignore are not part of the sources
+	assert.Equal(t, "Taskfile.yml\nsrc/main.txt\n", run("list"))
+	assert.Equal(t, "Taskfile.yml\nsrc/debug.log\nsrc/main.txt\ntmp/scratch.txt\n", run("list-all"))
+
+	assert.Equal(t, "building\n", run("build"))
+	assert.Empty(t, run("build"))
+	require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, "tmp/scratch.txt"), []byte("changed"), 0o644))
+	require.NoError(t, os.WriteFile(filepathext.SmartJoin(dir, "src/debug.log"), []byte("changed"), 0o644))
+	assert.Empty(t, run("build"))
+}
+
 func TestStatusVariables(t *testing.T) {
 	t.Parallel()
 
@@ -706,6 +989,62 @@ func TestDryChecksum(t *testing.T) {
 	require.NoError(t, err, "checksum file should exist")
 }
 
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/plan"
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+	)
+	require.NoError(t, e.Setup())
+
+	nodes, err := e.Plan(t.Context(), &task.Call{Task: "default"}, &task.Call{Task: "not-ready"})
+	require.NoError(t, err)
+	assert.Equal(t, []*task.PlanNode{
+		{
+			Task:   "default",
+			Status: task.PlanStatusRun,
+			Reason: "no sources or status",
+			Cmds:   []string{`echo "building"`},
+			Deps: []*task.PlanNode{
+				{Task: "generate", Status: task.PlanStatusUpToDate},
+				{Task: "lint", Status: task.PlanStatusRun, Reason: "no sources or status", Cmds: []string{`echo "linting"`}},
+			},
+			Calls: []*task.PlanNode{
+				{
+					Task:   "package",
+					Status: task.PlanStatusRun,
+					Reason: "no sources or status",
+					Cmds:   []string{`echo "packaging"`},
+					Deps: []*task.PlanNode{
+						{Task: "lint", Status: task.PlanStatusDeduplicated, Reason: "already part of the plan"},
+					},
+				},
+				{Task: "shard-a", Status: task.PlanStatusRun, Reason: "no sources or status", Cmds: []string{`echo "shard a"`}},
+				{Task: "shard-b", Status: task.PlanStatusRun, Reason: "no sources or status", Cmds: []string{`echo "shard b"`}},
+				{Task: "skipped", Status: task.PlanStatusSkipped, Reason: "if condition not met"},
+			},
+		},
+		{Task: "not-ready", Status: task.PlanStatusFail, Reason: "the database is not ready"},
+	}, nodes)
+	assert.Empty(t, buff.String(), "nothing should be run or logged")
+
+	require.NoError(t, e.PrintPlan(t.Context(), false, &task.Call{Task: "default"}))
+	assert.Equal(t, `default (run: no sources or status)
+├── generate (up-to-date)
+├── lint (run: no sources or status)
+├── package (run: no sources or status)
+│   └── lint (deduplicated: already part of the plan)
+├── shard-a (run: no sources or status)
+├── shard-b (run: no sources or status)
+└── skipped (skipped: if condition not met)
+`, buff.String())
+}
+
 func TestIncludes(t *testing.T) {
 	t.Parallel()
 
@@ -880,6 +1219,524 @@ func TestIncludesRemote(t *testing.T) {
 	}
 }
 
+func TestIncludesRemoteLock(t *testing.T) {
+	enableExperimentForTest(t, &experiments.RemoteTaskfiles, 1)
+
+	remoteDir := t.TempDir()
+	writeRemote := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(remoteDir, "Taskfile.yml"), []byte(content), 0o644))
+	}
+	writeRemote("version: '3'\n\ntasks:\n  hello: echo hello\n")
+	srv := httptest.NewServer(http.FileServer(http.Dir(remoteDir)))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	taskfile := fmt.Sprintf("version: '3'\n\nincludes:\n  remote: %s/Taskfile.yml\n", srv.URL)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(taskfile), 0o644))
+	lockfile := filepath.Join(dir, "Taskfile.lock")
+
+	setup := func(opts ...task.ExecutorOption) error {
+		var buff bytes.Buffer
+		e := task.NewExecutor(append([]task.ExecutorOption{
+			task.WithDir(dir),
+			task.WithStdout(&buff),
+			task.WithStderr(&buff),
+			task.WithInsecure(true),
+			task.WithAssumeYes(true),
+			task.WithTempDir(task.TempDir{Remote: filepath.Join(dir, ".task"), Fingerprint: filepath.Join(dir, ".task")}),
+		}, opts...)...)
+		return e.Setup()
+	}
+
+	// Without a lock file, nothing is verified
+	require.NoError(t, setup())
+	assert.NoFileExists(t, lockfile)
+
+	require.NoError(t, setup(task.WithLock(true)))
+	data, err := os.ReadFile(lockfile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), srv.URL+"/Taskfile.yml")
+	require.NoError(t, setup())
+
+	// Changes to the remote Taskfile are detected
+	writeRemote("version: '3'\n\ntasks:\n  hello: echo changed\n")
+	err = setup()
+	var checksumErr *errors.TaskfileDoesNotMatchChecksum
+	require.ErrorAs(t, err, &checksumErr)
+	assert.Equal(t, lockfile, checksumErr.Lockfile)
+	require.ErrorAs(t, setup(task.WithLock(true)), &checksumErr)
+
+	require.NoError(t, setup(task.WithUpdateLock(true)))
+	require.NoError(t, setup())
+	updated, err := os.ReadFile(lockfile)
+	require.NoError(t, err)
+	assert.NotEqual(t, string(data), string(updated))
+
+	// Remote Taskfiles that are not in the lock file are rejected
+	require.NoError(t, os.WriteFile(lockfile, []byte("version: 1\nremotes: {}\n"), 0o644))
+	var notLockedErr *errors.TaskfileNotLockedError
+	require.ErrorAs(t, setup(), &notLockedErr)
+}
+
+func TestIncludesRemoteSignature(t *testing.T) {
+	enableExperimentForTest(t, &experiments.RemoteTaskfiles, 1)
+
+	newKey := func(name string) (string, string) {
+		publicKey, privateKey, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		block, err := ssh.MarshalPrivateKey(privateKey, "")
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0o600))
+		sshPublicKey, err := ssh.NewPublicKey(publicKey)
+		require.NoError(t, err)
+		return path, string(ssh.MarshalAuthorizedKey(sshPublicKey))
+	}
+	trustedKey, trustedPublicKey := newKey("trusted")
+	otherKey, _ := newKey("other")
+
+	remoteDir := t.TempDir()
+	remoteTaskfile := filepath.Join(remoteDir, "Taskfile.yml")
+	require.NoError(t, os.WriteFile(remoteTaskfile, []byte("version: '3'\n\ntasks:\n  hello: echo hello\n"), 0o644))
+	srv := httptest.NewServer(http.FileServer(http.Dir(remoteDir)))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	root := fmt.Sprintf("version: '3'\n\nincludes:\n  remote: %s/Taskfile.yml\n", srv.URL)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(root), 0o644))
+
+	// Signed Taskfiles are trusted without prompting
+	setupWithKeys := func(keys []string, opts ...task.ExecutorOption) error {
+		var buff bytes.Buffer
+		e := task.NewExecutor(append([]task.ExecutorOption{
+			task.WithDir(dir),
+			task.WithStdin(strings.NewReader("")),
+			task.WithStdout(&buff),
+			task.WithStderr(&buff),
+			task.WithInsecure(true),
+			task.WithTrustedKeys(keys),
+			task.WithTempDir(task.TempDir{Remote: filepath.Join(dir, ".task"), Fingerprint: filepath.Join(dir, ".task")}),
+		}, opts...)...)
+		return e.Setup()
+	}
+	setup := func(opts ...task.ExecutorOption) error {
+		return setupWithKeys([]string{trustedPublicKey}, opts...)
+	}
+
+	var sigErr *errors.TaskfileSignatureError
+	require.ErrorAs(t, setup(), &sigErr)
+	assert.Equal(t, errors.SignatureMissing, sigErr.Reason)
+
+	_, err := taskfile.Sign(remoteTaskfile, otherKey)
+	require.NoError(t, err)
+	require.ErrorAs(t, setup(), &sigErr)
+	assert.Equal(t, errors.SignatureUntrusted, sigErr.Reason)
+
+	sigPath, err := taskfile.Sign(remoteTaskfile, trustedKey)
+	require.NoError(t, err)
+	assert.Equal(t, remoteTaskfile+".sig", sigPath)
+	require.NoError(t, setup())
+
+	// Changes made after signing are detected
+	require.NoError(t, os.WriteFile(remoteTaskfile, []byte("version: '3'\n\ntasks:\n  hello: echo changed\n"), 0o644))
+	require.ErrorAs(t, setup(), &sigErr)
+	assert.Equal(t, errors.SignatureInvalid, sigErr.Reason)
+
+	// Cached copies are verified too
+	_, err = taskfile.Sign(remoteTaskfile, trustedKey)
+	require.NoError(t, err)
+	require.NoError(t, setup())
+	cached, err := filepath.Glob(filepath.Join(dir, ".task", "remote", "*.yaml"))
+	require.NoError(t, err)
+	require.Len(t, cached, 1)
+	require.NoError(t, setup(task.WithOffline(true)))
+	require.NoError(t, os.WriteFile(cached[0], []byte("version: '3'\n\ntasks:\n  hello: echo tampered\n"), 0o644))
+	require.ErrorAs(t, setup(task.WithOffline(true)), &sigErr)
+	assert.Equal(t, errors.SignatureInvalid, sigErr.Reason)
+
+	// Including copies cached before the keys were trusted
+	require.NoError(t, os.Remove(sigPath))
+	require.NoError(t, setupWithKeys(nil, task.WithAssumeYes(true)))
+	require.ErrorAs(t, setup(task.WithOffline(true)), &sigErr)
+	assert.Equal(t, errors.SignatureMissing, sigErr.Reason)
+
+	// And vendored copies
+	_, err = taskfile.Sign(remoteTaskfile, trustedKey)
+	require.NoError(t, err)
+	require.NoError(t, setup(task.WithVendor(true)))
+	vendored, err := filepath.Glob(filepath.Join(dir, ".task", "vendor", "*.yaml"))
+	require.NoError(t, err)
+	require.Len(t, vendored, 1)
+	require.NoError(t, setup())
+	require.NoError(t, os.WriteFile(vendored[0], []byte("version: '3'\n\ntasks:\n  hello: echo tampered\n"), 0o644))
+	require.ErrorAs(t, setup(), &sigErr)
+	assert.Equal(t, errors.SignatureInvalid, sigErr.Reason)
+}
+
+func TestIncludesRemoteVendor(t *testing.T) {
+	enableExperimentForTest(t, &experiments.RemoteTaskfiles, 1)
+
+	remoteDir := t.TempDir()
+	writeRemote := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(remoteDir, "Taskfile.yml"), []byte(content), 0o644))
+	}
+	writeRemote("version: '3'\n\nincludes:\n  nested: ./nested.yml\n\ntasks:\n  hello: echo hello\n")
+	require.NoError(t, os.WriteFile(filepath.Join(remoteDir, "nested.yml"), []byte("version: '3'\n\ntasks:\n  hello: echo nested\n"), 0o644))
+	srv := httptest.NewServer(http.FileServer(http.Dir(remoteDir)))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	taskfile := fmt.Sprintf("version: '3'\n\nincludes:\n  remote: %s/Taskfile.yml\n", srv.URL)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(taskfile), 0o644))
+
+	run := func(opts ...task.ExecutorOption) (string, error) {
+		var buff SyncBuffer
+		e := task.NewExecutor(append([]task.ExecutorOption{
+			task.WithDir(dir),
+			task.WithStdout(&buff),
+			task.WithStderr(&buff),
+			task.WithSilent(true),
+			task.WithInsecure(true),
+			task.WithAssumeYes(true),
+			task.WithTempDir(task.TempDir{Remote: filepath.Join(dir, ".task"), Fingerprint: filepath.Join(dir, ".task")}),
+		}, opts...)...)
+		if err := e.Setup(); err != nil {
+			return "", err
+		}
+		err := e.Run(t.Context(), &task.Call{Task: "remote:hello"}, &task.Call{Task: "remote:nested:hello"})
+		return buff.buf.String(), err
+	}
+
+	require.NoError(t, task.NewExecutor(
+		task.WithDir(dir),
+		task.WithInsecure(true),
+		task.WithAssumeYes(true),
+		task.WithStdout(io.Discard),
+		task.WithStderr(io.Discard),
+		task.WithVendor(true),
+	).Setup())
+	entries, err := os.ReadDir(filepath.Join(dir, ".task", "vendor"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	// The vendored copies are used instead of the network
+	writeRemote("version: '3'\n\ntasks:\n  hello: echo changed\n")
+	srv.Close()
+	out, err := run()
+	require.NoError(t, err)
+	assert.Equal(t, "hello\nnested\n", out)
+
+	// Forcing a download ignores them
+	_, err = run(task.WithDownload(true))
+	require.Error(t, err)
+}
+
+func TestIncludesRemoteGitCache(t *testing.T) {
+	enableExperimentForTest(t, &experiments.RemoteTaskfiles, 1)
+
+	gitBin, err := exec.LookPath("git")
+	require.NoError(t, err)
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=task", "-c", "user.email=task@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	// Serve a repository with the smart HTTP protocol, which supports shallow
+	// clones
+	remoteDir := t.TempDir()
+	workDir := t.TempDir()
+	git(remoteDir, "init", "--quiet", "--bare", "--initial-branch=main", "repo.git")
+	git(workDir, "init", "--quiet", "--initial-branch=main")
+	git(workDir, "remote", "add", "origin", filepath.Join(remoteDir, "repo.git"))
+	commit := func(content string) string {
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, "Taskfile.yml"), []byte(content), 0o644))
+		git(workDir, "add", "Taskfile.yml")
+		git(workDir, "commit", "--quiet", "-m", "update")
+		git(workDir, "push", "--quiet", "origin", "main")
+		return git(workDir, "rev-parse", "HEAD")
+	}
+	first := commit("version: '3'\n\ntasks:\n  commit: echo {{.TASKFILE_COMMIT}}\n")
+	srv := httptest.NewServer(&cgi.Handler{
+		Path: gitBin,
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=" + remoteDir, "GIT_HTTP_EXPORT_ALL=1"},
+	})
+	defer srv.Close()
+
+	dir := t.TempDir()
+	root := fmt.Sprintf("version: '3'\n\nincludes:\n  remote: %s/repo.git//Taskfile.yml?ref=main\n", srv.URL)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(root), 0o644))
+
+	run := func(opts ...task.ExecutorOption) string {
+		var buff SyncBuffer
+		e := task.NewExecutor(append([]task.ExecutorOption{
+			task.WithDir(dir),
+			task.WithStdout(&buff),
+			task.WithStderr(&buff),
+			task.WithSilent(true),
+			task.WithInsecure(true),
+			task.WithAssumeYes(true),
+			task.WithCacheExpiryDuration(time.Hour),
+			task.WithTempDir(task.TempDir{Remote: filepath.Join(dir, ".task"), Fingerprint: filepath.Join(dir, ".task")}),
+		}, opts...)...)
+		require.NoError(t, e.Setup())
+		require.NoError(t, e.Run(t.Context(), &task.Call{Task: "remote:commit"}))
+		// The output ends with the one of the task, after the trust prompts
+		lines := strings.Split(strings.TrimSpace(buff.buf.String()), "\n")
+		return lines[len(lines)-1]
+	}
+
+	// The repository is cloned in the remote cache, and kept after reading
+	assert.Equal(t, first, run())
+	assert.DirExists(t, filepath.Join(dir, ".task", "git"))
+
+	// The commit is also known when the Taskfile is read from the cache
+	second := commit("version: '3'\n\ntasks:\n  commit: echo {{.TASKFILE_COMMIT}} updated\n")
+	assert.Equal(t, first, run())
+
+	// Expired clones are fetched again
+	assert.Equal(t, second+" updated", run(task.WithDownload(true)))
+	assert.Equal(t, second+" updated", run(task.WithCacheExpiryDuration(0)))
+}
+
+func TestIncludesRemoteGitVendor(t *testing.T) {
+	enableExperimentForTest(t, &experiments.RemoteTaskfiles, 1)
+
+	gitBin, err := exec.LookPath("git")
+	require.NoError(t, err)
+	git := func(dir string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=task", "-c", "user.email=task@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	// A repository whose Taskfile runs a script of the repository
+	remoteDir := t.TempDir()
+	workDir := t.TempDir()
+	git(remoteDir, "init", "--quiet", "--bare", "--initial-branch=main", "repo.git")
+	git(workDir, "init", "--quiet", "--initial-branch=main")
+	git(workDir, "remote", "add", "origin", filepath.Join(remoteDir, "repo.git"))
+	for name, content := range map[string]string{
+		"tasks/Taskfile.yml": "version: '3'\n\ntasks:\n  hello: sh {{.TASKFILE_DIR}}/../scripts/hello.sh\n",
+		"scripts/hello.sh":   "echo hello from script\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(workDir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, name), []byte(content), 0o644))
+	}
+	git(workDir, "add", ".")
+	git(workDir, "commit", "--quiet", "-m", "initial")
+	git(workDir, "push", "--quiet", "origin", "main")
+	srv := httptest.NewServer(&cgi.Handler{
+		Path: gitBin,
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=" + remoteDir, "GIT_HTTP_EXPORT_ALL=1"},
+	})
+	defer srv.Close()
+
+	dir := t.TempDir()
+	root := fmt.Sprintf("version: '3'\n\nincludes:\n  remote: %s/repo.git//tasks/Taskfile.yml?ref=main\n", srv.URL)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(root), 0o644))
+
+	newExecutor := func(buff io.Writer, opts ...task.ExecutorOption) *task.Executor {
+		return task.NewExecutor(append([]task.ExecutorOption{
+			task.WithDir(dir),
+			task.WithStdout(buff),
+			task.WithStderr(buff),
+			task.WithSilent(true),
+			task.WithInsecure(true),
+			task.WithAssumeYes(true),
+			task.WithTempDir(task.TempDir{Remote: filepath.Join(dir, ".task"), Fingerprint: filepath.Join(dir, ".task")}),
+		}, opts...)...)
+	}
+
+	// The files of the repository are vendored along with the Taskfile
+	require.NoError(t, newExecutor(io.Discard, task.WithVendor(true)).Setup())
+	scripts, err := filepath.Glob(filepath.Join(dir, ".task", "vendor", "*", "scripts", "hello.sh"))
+	require.NoError(t, err)
+	assert.Len(t, scripts, 1)
+
+	// And used without the network, the cache or the clone
+	srv.Close()
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, ".task", "git")))
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, ".task", "remote")))
+	var buff SyncBuffer
+	e := newExecutor(&buff, task.WithOffline(true))
+	require.NoError(t, e.Setup())
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "remote:hello"}))
+	assert.Equal(t, "hello from script\n", buff.buf.String())
+}
+
+func TestIncludesRemoteGitCredentials(t *testing.T) {
+	enableExperimentForTest(t, &experiments.RemoteTaskfiles, 1)
+
+	gitBin, err := exec.LookPath("git")
+	require.NoError(t, err)
+	git := func(dir string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=task", "-c", "user.email=task@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	// A repository served only to the requests authenticated with the token
+	remoteDir := t.TempDir()
+	workDir := t.TempDir()
+	git(remoteDir, "init", "--quiet", "--bare", "--initial-branch=main", "repo.git")
+	git(workDir, "init", "--quiet", "--initial-branch=main")
+	git(workDir, "remote", "add", "origin", filepath.Join(remoteDir, "repo.git"))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "Taskfile.yml"), []byte("version: '3'\n\ntasks:\n  hello: echo hello\n"), 0o644))
+	git(workDir, "add", ".")
+	git(workDir, "commit", "--quiet", "-m", "initial")
+	git(workDir, "push", "--quiet", "origin", "main")
+	backend := &cgi.Handler{
+		Path: gitBin,
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=" + remoteDir, "GIT_HTTP_EXPORT_ALL=1"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "x-access-token" || password != "s$cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		backend.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	root := fmt.Sprintf("version: '3'\n\nincludes:\n  remote: %s/repo.git//Taskfile.yml?ref=main\n", srv.URL)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(root), 0o644))
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	var buff SyncBuffer
+	e := task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+		task.WithInsecure(true),
+		task.WithAssumeYes(true),
+		task.WithCredentials(map[string]taskfile.Credentials{u.Host: {Token: "s$cret"}}),
+		task.WithTempDir(task.TempDir{Remote: filepath.Join(dir, ".task"), Fingerprint: filepath.Join(dir, ".task")}),
+	)
+	require.NoError(t, e.Setup())
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "remote:hello"}))
+	assert.Contains(t, buff.buf.String(), "hello\n")
+
+	// The token is not stored in the clone
+	configs, err := filepath.Glob(filepath.Join(dir, ".task", "git", "*", "*", "*", ".git", "config"))
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	config, err := os.ReadFile(configs[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(config), "s$cret")
+}
+
+func TestIncludesRemoteArchive(t *testing.T) {
+	enableExperimentForTest(t, &experiments.RemoteTaskfiles, 1)
+
+	newArchive := func(script string) *bytes.Buffer {
+		var archive bytes.Buffer
+		zw := zip.NewWriter(&archive)
+		for name, content := range map[string]string{
+			"lib/Taskfile.yml":     "version: '3'\n\nincludes:\n  sub: ./sub\n\ntasks:\n  dir: echo {{.TASKFILE_DIR}}\n",
+			"lib/sub/Taskfile.yml": "version: '3'\n\ntasks:\n  hello: echo hello from sub\n",
+			"lib/scripts/hello.sh": script,
+		} {
+			w, err := zw.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zw.Close())
+		return &archive
+	}
+	archive := newArchive("echo hello\n")
+
+	remoteDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(remoteDir, "lib.zip"), archive.Bytes(), 0o644))
+	srv := httptest.NewServer(http.FileServer(http.Dir(remoteDir)))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lib.zip"), archive.Bytes(), 0o644))
+	root := fmt.Sprintf("version: '3'\n\nincludes:\n  local: ./lib.zip\n  remote: %s/lib.zip//lib/Taskfile.yml?token=x\n", srv.URL)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(root), 0o644))
+
+	run := func(name string) string {
+		var buff SyncBuffer
+		e := task.NewExecutor(
+			task.WithDir(dir),
+			task.WithStdout(&buff),
+			task.WithStderr(&buff),
+			task.WithSilent(true),
+			task.WithInsecure(true),
+			task.WithAssumeYes(true),
+			task.WithTempDir(task.TempDir{Remote: filepath.Join(dir, ".task"), Fingerprint: filepath.Join(dir, ".task")}),
+		)
+		require.NoError(t, e.Setup())
+		require.NoError(t, e.Run(t.Context(), &task.Call{Task: name}))
+		// The output ends with the one of the task, after the trust prompts
+		lines := strings.Split(strings.TrimSpace(buff.buf.String()), "\n")
+		return lines[len(lines)-1]
+	}
+
+	// The tasks of archives are located in the extracted archive, so that they
+	// can use its other files
+	for _, name := range []string{"local", "remote"} {
+		taskfileDir := run(name + ":dir")
+		assert.FileExists(t, filepath.Join(taskfileDir, "scripts", "hello.sh"))
+		assert.True(t, strings.HasPrefix(taskfileDir, filepath.Join(dir, ".task", "archive")), taskfileDir)
+		assert.Equal(t, "hello from sub", run(name+":sub:hello"))
+	}
+
+	// The checksum of archives covers all of their files, not only the
+	// Taskfile
+	sum := sha256.Sum256(archive.Bytes())
+	pinned := fmt.Sprintf("version: '3'\n\nincludes:\n  remote:\n    taskfile: %s/lib.zip//lib/Taskfile.yml\n    checksum: %x\n", srv.URL, sum)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(pinned), 0o644))
+	assert.Equal(t, "hello from sub", run("remote:sub:hello"))
+	require.NoError(t, os.WriteFile(filepath.Join(remoteDir, "lib.zip"), newArchive("echo changed\n").Bytes(), 0o644))
+	e := task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(io.Discard),
+		task.WithStderr(io.Discard),
+		task.WithInsecure(true),
+		task.WithAssumeYes(true),
+		task.WithTempDir(task.TempDir{Remote: filepath.Join(dir, ".task"), Fingerprint: filepath.Join(dir, ".task")}),
+	)
+	var checksumErr *errors.TaskfileDoesNotMatchChecksum
+	require.ErrorAs(t, e.Setup(), &checksumErr)
+	assert.Equal(t, fmt.Sprintf("%x", sum), checksumErr.ExpectedChecksum)
+
+	// The extracted archive is vendored along with the Taskfile, and used
+	// without the network or the cache
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(root), 0o644))
+	e = task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(io.Discard),
+		task.WithStderr(io.Discard),
+		task.WithInsecure(true),
+		task.WithAssumeYes(true),
+		task.WithVendor(true),
+		task.WithTempDir(task.TempDir{Remote: filepath.Join(dir, ".task"), Fingerprint: filepath.Join(dir, ".task")}),
+	)
+	require.NoError(t, e.Setup())
+	srv.Close()
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, ".task", "archive")))
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, ".task", "remote")))
+	taskfileDir := run("remote:dir")
+	assert.True(t, strings.HasPrefix(taskfileDir, filepath.Join(dir, ".task", "vendor")), taskfileDir)
+	script, err := os.ReadFile(filepath.Join(taskfileDir, "scripts", "hello.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "echo changed\n", string(script))
+	assert.Equal(t, "hello from sub", run("remote:sub:hello"))
+}
+
 func TestIncludeCycle(t *testing.T) {
 	t.Parallel()
 
@@ -1018,57 +1875,491 @@ func TestIncludesHttp(t *testing.T) {
 	}
 }
 
-func TestIncludesDependencies(t *testing.T) {
+func TestIncludesDependencies(t *testing.T) {
+	t.Parallel()
+
+	tt := fileContentTest{
+		Dir:       "testdata/includes_deps",
+		Target:    "default",
+		TrimSpace: true,
+		Files: map[string]string{
+			"default.txt":     "default",
+			"called_dep.txt":  "called_dep",
+			"called_task.txt": "called_task",
+		},
+	}
+	t.Run("", func(t *testing.T) {
+		t.Parallel()
+		tt.Run(t)
+	})
+}
+
+func TestIncludesCallingRoot(t *testing.T) {
+	t.Parallel()
+
+	tt := fileContentTest{
+		Dir:       "testdata/includes_call_root_task",
+		Target:    "included:call-root",
+		TrimSpace: true,
+		Files: map[string]string{
+			"root_task.txt": "root task",
+		},
+	}
+	t.Run("", func(t *testing.T) {
+		t.Parallel()
+		tt.Run(t)
+	})
+}
+
+func TestIncludesOptional(t *testing.T) {
+	t.Parallel()
+
+	tt := fileContentTest{
+		Dir:       "testdata/includes_optional",
+		Target:    "default",
+		TrimSpace: true,
+		Files: map[string]string{
+			"called_dep.txt": "called_dep",
+		},
+	}
+	t.Run("", func(t *testing.T) {
+		t.Parallel()
+		tt.Run(t)
+	})
+}
+
+func TestIncludesIf(t *testing.T) {
+	t.Parallel()
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir("testdata/includes_if"),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+	)
+	// The include whose condition isn't met is skipped, even if its Taskfile
+	// doesn't exist
+	require.NoError(t, e.Setup())
+
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "included:hello"}))
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "local:hello"}))
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "env:hello"}))
+	assert.Equal(t, "hello\nhello\nhello\n", buff.String())
+
+	err := e.Run(t.Context(), &task.Call{Task: "ci:hello"})
+	var taskNotFoundErr *errors.TaskNotFoundError
+	assert.ErrorAs(t, err, &taskNotFoundErr)
+
+	// Invalid conditions are errors, instead of skipping the include
+	dir := t.TempDir()
+	taskfile := "version: '3'\n\nincludes:\n  invalid:\n    taskfile: ./Missing.yml\n    if: 'if then'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(taskfile), 0o644))
+	e = task.NewExecutor(task.WithDir(dir), task.WithStdout(io.Discard), task.WithStderr(io.Discard))
+	assert.ErrorContains(t, e.Setup(), `if condition of include "invalid"`)
+}
+
+func TestIncludesGlob(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/includes_glob"
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+	)
+	require.NoError(t, e.Setup())
+
+	// Each match is included in a namespace named after its directory, and
+	// its tasks run in this directory
+	tests := []struct {
+		task   string
+		output string
+	}{
+		{"packages:api:build", filepath.Join(wd, dir, "packages", "api")},
+		{"packages:web:build", filepath.Join(wd, dir, "packages", "web")},
+		{"pkg:web:build", filepath.Join(wd, dir, "packages", "web")},
+		{"lint", "lint"},
+	}
+	for _, test := range tests {
+		buff.Reset()
+		require.NoError(t, e.Run(t.Context(), &task.Call{Task: test.task}), test.task)
+		assert.Equal(t, test.output, strings.TrimSpace(buff.String()), test.task)
+	}
+
+	var taskNotFoundErr *errors.TaskNotFoundError
+	for _, name := range []string{"packages:web:internal", "packages:docs:build"} {
+		assert.ErrorAs(t, e.Run(t.Context(), &task.Call{Task: name}), &taskNotFoundErr, name)
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		recursive bool
+		call      string
+	}{
+		{"recursive", true, "build"},
+		{"pattern", false, "packages:*:build"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buff bytes.Buffer
+			e := task.NewExecutor(
+				task.WithDir("testdata/recursive"),
+				task.WithStdout(&buff),
+				task.WithStderr(&buff),
+				task.WithSilent(true),
+				task.WithRecursive(test.recursive),
+			)
+			require.NoError(t, e.Setup())
+			require.NoError(t, e.Run(t.Context(), &task.Call{Task: test.call}))
+
+			// The root task is not run and the packages are run in the order
+			// of their dependencies
+			assert.Equal(t, "lib\napi\nweb\n", buff.String())
+		})
+	}
+
+	// Patterns and regular tasks can be given together and are run in order
+	mixedTests := []struct {
+		calls    []string
+		expected string
+	}{
+		{[]string{"packages:*:build", "build"}, "lib\napi\nweb\nroot\n"},
+		{[]string{"build", "packages:*:build"}, "root\nlib\napi\nweb\n"},
+	}
+	for _, test := range mixedTests {
+		var buff bytes.Buffer
+		e := task.NewExecutor(
+			task.WithDir("testdata/recursive"),
+			task.WithStdout(&buff),
+			task.WithStderr(&buff),
+			task.WithSilent(true),
+		)
+		require.NoError(t, e.Setup())
+		calls := make([]*task.Call, 0, len(test.calls))
+		for _, call := range test.calls {
+			calls = append(calls, &task.Call{Task: call})
+		}
+		require.NoError(t, e.Run(t.Context(), calls...))
+		assert.Equal(t, test.expected, buff.String())
+	}
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir("testdata/recursive"),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+	)
+	require.NoError(t, e.Setup())
+	var taskNotFoundErr *errors.TaskNotFoundError
+	assert.ErrorAs(t, e.Run(t.Context(), &task.Call{Task: "packages:*:build"}, &task.Call{Task: "missing"}), &taskNotFoundErr)
+
+	buff.Reset()
+	e = task.NewExecutor(
+		task.WithDir("testdata/recursive"),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+		task.WithRecursive(true),
+	)
+	require.NoError(t, e.Setup())
+	assert.ErrorAs(t, e.Run(t.Context(), &task.Call{Task: "test"}), &taskNotFoundErr)
+
+	e = task.NewExecutor(
+		task.WithDir("testdata/recursive_cycle"),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+		task.WithRecursive(true),
+	)
+	require.NoError(t, e.Setup())
+	assert.ErrorContains(t, e.Run(t.Context(), &task.Call{Task: "build"}), "cycle")
+}
+
+func TestAffectedSince(t *testing.T) {
 	t.Parallel()
 
-	tt := fileContentTest{
-		Dir:       "testdata/includes_deps",
-		Target:    "default",
-		TrimSpace: true,
-		Files: map[string]string{
-			"default.txt":     "default",
-			"called_dep.txt":  "called_dep",
-			"called_task.txt": "called_task",
-		},
+	git := func(dir string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=task", "-c", "user.email=task@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
 	}
-	t.Run("", func(t *testing.T) {
-		t.Parallel()
-		tt.Run(t)
-	})
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	writeFile("Taskfile.yml", `version: '3'
+
+tasks:
+  default:
+    deps: [api, web]
+
+  lib:
+    sources: [lib/*.go]
+    cmds: [echo lib]
+
+  api:
+    sources: [api/*.go]
+    deps: [lib]
+    cmds: [echo api]
+
+  web:
+    sources: ['{{.DIR}}/*.go']
+    vars:
+      DIR: web
+    cmds: [echo web]
+
+  docs:
+    cmds: [echo docs]
+
+  pkg:
+    sources: ['{{.PKG}}/*.go']
+    cmds: ['echo {{.PKG}}']
+
+  broken:
+    vars:
+      FAIL:
+        sh: exit 1
+    cmds: [echo broken]
+
+  cli:
+    sources:
+      - cli/*.go
+      - exclude: cli/*_test.go
+    cmds: [echo cli]
+`)
+	writeFile("cli/a.go", "package cli\n")
+	writeFile("cli/b.go", "package cli\n")
+	writeFile("cli/b_test.go", "package cli\n")
+	writeFile("lib/lib.go", "package lib\n")
+	writeFile("api/api.go", "package api\n")
+	writeFile("web/web.go", "package web\n")
+	git(dir, "init", "--quiet", "--initial-branch=main")
+	git(dir, "add", ".")
+	git(dir, "commit", "--quiet", "-m", "initial")
+	git(dir, "branch", "base")
+
+	// A committed change in a dependency
+	writeFile("lib/lib.go", "package lib\n\nconst Version = 1\n")
+	git(dir, "commit", "--quiet", "-am", "update lib")
+
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+		task.WithAffectedSince("base"),
+	)
+	require.NoError(t, e.Setup())
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "api"}, &task.Call{Task: "web"}))
+	assert.Equal(t, "lib\napi\n", buff.String())
+
+	buff.Reset()
+	_, err := e.ListTasks(task.ListOptions{ListAllTasks: true})
+	require.NoError(t, err)
+	assert.Contains(t, buff.String(), "* api")
+	assert.Contains(t, buff.String(), "* default")
+	assert.Contains(t, buff.String(), "* lib")
+	assert.NotContains(t, buff.String(), "* web")
+	assert.NotContains(t, buff.String(), "* docs")
+	assert.Contains(t, buff.String(), "* broken")
+
+	// The same task called with different variables
+	pkg := func(name string) *task.Call {
+		call := &task.Call{Task: "pkg", Vars: ast.NewVars()}
+		call.Vars.Set("PKG", ast.Var{Value: name})
+		return call
+	}
+	buff.Reset()
+	require.NoError(t, e.Run(t.Context(), pkg("web"), pkg("lib")))
+	assert.Equal(t, "lib\n", buff.String())
+
+	// An untracked file matching templated sources
+	writeFile("web/new.go", "package web\n")
+	buff.Reset()
+	e = task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+		task.WithAffectedSince("base"),
+	)
+	require.NoError(t, e.Setup())
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "web"}, &task.Call{Task: "docs"}))
+	assert.Equal(t, "web\n", buff.String())
+
+	// Deleted sources, unless they are excluded
+	git(dir, "add", ".")
+	git(dir, "commit", "--quiet", "-m", "add web")
+	git(dir, "branch", "before-delete")
+	git(dir, "rm", "--quiet", "cli/b_test.go")
+	git(dir, "commit", "--quiet", "-m", "delete test")
+	for _, deleted := range []bool{false, true} {
+		if deleted {
+			git(dir, "rm", "--quiet", "cli/b.go")
+			git(dir, "commit", "--quiet", "-m", "delete source")
+		}
+		buff.Reset()
+		e = task.NewExecutor(
+			task.WithDir(dir),
+			task.WithStdout(&buff),
+			task.WithStderr(&buff),
+			task.WithSilent(true),
+			task.WithAffectedSince("before-delete"),
+		)
+		require.NoError(t, e.Setup())
+		require.NoError(t, e.Run(t.Context(), &task.Call{Task: "cli"}))
+		assert.Equal(t, deleted, buff.String() == "cli\n", buff.String())
+	}
+
+	e = task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithAffectedSince("missing"),
+	)
+	require.NoError(t, e.Setup())
+	assert.Error(t, e.Run(t.Context(), &task.Call{Task: "api"}))
 }
 
-func TestIncludesCallingRoot(t *testing.T) {
+func TestExtends(t *testing.T) {
 	t.Parallel()
 
-	tt := fileContentTest{
-		Dir:       "testdata/includes_call_root_task",
-		Target:    "included:call-root",
-		TrimSpace: true,
-		Files: map[string]string{
-			"root_task.txt": "root task",
-		},
+	tests := []struct {
+		task   string
+		output string
+	}{
+		{"greet", "setup\nHello, World.\n"},
+		{"greet-loud", "setup\nHELLO, WORLD!\n"},
 	}
-	t.Run("", func(t *testing.T) {
-		t.Parallel()
-		tt.Run(t)
-	})
+	for _, test := range tests {
+		t.Run(test.task, func(t *testing.T) {
+			t.Parallel()
+
+			var buff bytes.Buffer
+			e := task.NewExecutor(
+				task.WithDir("testdata/extends"),
+				task.WithStdout(&buff),
+				task.WithStderr(&buff),
+				task.WithSilent(true),
+			)
+			require.NoError(t, e.Setup())
+			require.NoError(t, e.Run(t.Context(), &task.Call{Task: test.task}))
+			assert.Equal(t, test.output, buff.String())
+		})
+	}
+
+	// Tasks can set the boolean fields of the tasks they extend back to false
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir("testdata/extends"),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+	)
+	require.NoError(t, e.Setup())
+	require.Error(t, e.Run(t.Context(), &task.Call{Task: "strict"}))
+	assert.NotContains(t, buff.String(), "after error")
 }
 
-func TestIncludesOptional(t *testing.T) {
+func TestHooks(t *testing.T) {
 	t.Parallel()
 
-	tt := fileContentTest{
-		Dir:       "testdata/includes_optional",
-		Target:    "default",
-		TrimSpace: true,
-		Files: map[string]string{
-			"called_dep.txt": "called_dep",
+	const dir = "testdata/hooks"
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	tests := []struct {
+		task   string
+		output []string
+		err    bool
+	}{
+		{
+			task: "default",
+			output: []string{
+				"before all default",
+				"before default",
+				"before setup",
+				"setup",
+				"after setup 0",
+				"default",
+				"after default 0",
+				"after all 0",
+			},
+		},
+		{
+			// The hooks of included Taskfiles only apply to their namespace
+			task: "lib:build",
+			output: []string{
+				"before all lib:build",
+				"before lib:build",
+				"lib before lib:build hello lib env",
+				"build",
+				filepath.Join(wd, dir, "lib"),
+				"after lib:build 0",
+				"after all 0",
+			},
+		},
+		{
+			// The hooks of flattened Taskfiles only apply to their tasks
+			task: "flat-build",
+			output: []string{
+				"before all flat-build",
+				"before flat-build",
+				"flat before flat-build",
+				"flat build",
+				"after flat-build 0",
+				"after all 0",
+			},
+		},
+		{
+			task: "fail",
+			output: []string{
+				"before all fail",
+				"before fail",
+				"failed fail 3",
+				"after fail 3",
+				"after all 3",
+			},
+			err: true,
 		},
 	}
-	t.Run("", func(t *testing.T) {
-		t.Parallel()
-		tt.Run(t)
-	})
+	for _, test := range tests {
+		t.Run(test.task, func(t *testing.T) {
+			t.Parallel()
+
+			var buff bytes.Buffer
+			e := task.NewExecutor(
+				task.WithDir(dir),
+				task.WithStdout(&buff),
+				task.WithStderr(&buff),
+				task.WithSilent(true),
+			)
+			require.NoError(t, e.Setup())
+			err := e.Run(t.Context(), &task.Call{Task: test.task})
+			if test.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, strings.Join(test.output, "\n")+"\n", buff.String())
+		})
+	}
 }
 
 func TestIncludesOptionalImplicitFalse(t *testing.T) {
@@ -1927,6 +3218,244 @@ task-1 ran successfully
 	assert.Contains(t, buff.String(), "child task deferred value-from-parent")
 }
 
+func TestDeferredOn(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/deferred"
+	var buff bytes.Buffer
+	e := task.NewExecutor(
+		task.WithDir(dir),
+		task.WithStdout(&buff),
+		task.WithStderr(&buff),
+		task.WithSilent(true),
+	)
+	require.NoError(t, e.Setup())
+
+	require.NoError(t, e.Run(t.Context(), &task.Call{Task: "on-success"}))
+	assert.Equal(t, "working\ndeferred on success\ndeferred always\n", buff.String())
+
+	buff.Reset()
+	require.Error(t, e.Run(t.Context(), &task.Call{Task: "on-failure"}))
+	assert.Equal(t, strings.Join([]string{
+		"working",
+		`deferred on failure of "exit 3" with 3`,
+		"rollback exit status 3",
+		"deferred always",
+		"",
+	}, "\n"), buff.String())
+}
+
+func TestRetries(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/retries"
+
+	tests := []struct {
+		name         string
+		task         string
+		wantErr      bool
+		wantExitCode int
+		expected     []string
+		unexpected   []string
+	}{
+		{
+			name: "cmd succeeds after retries",
+			task: "cmd-retry",
+			expected: []string{
+				"task: [cmd-retry] attempt 1/4 failed: exit status 1 - retrying in 10ms",
+				"task: [cmd-retry] attempt 2/4 failed: exit status 1 - retrying in 20ms",
+				"succeeded on attempt 3",
+			},
+			unexpected: []string{"attempt 3/4 failed"},
+		},
+		{
+			name:         "cmd retries exhausted",
+			task:         "cmd-retry-exhausted",
+			wantErr:      true,
+			wantExitCode: 3,
+			expected: []string{
+				"attempt 1\n",
+				"task: [cmd-retry-exhausted] attempt 1/3 failed: exit status 3 - retrying",
+				"attempt 2\n",
+				"task: [cmd-retry-exhausted] attempt 2/3 failed: exit status 3 - retrying",
+				"attempt 3\n",
+			},
+			unexpected: []string{"attempt 3/3 failed", "attempt 4"},
+		},
+		{
+			name:         "cmd not retried on unlisted exit code",
+			task:         "cmd-retry-exit-codes",
+			wantErr:      true,
+			wantExitCode: 2,
+			expected:     []string{"attempt 1\n"},
+			unexpected:   []string{"retrying", "attempt 2"},
+		},
+		{
+			name: "ignore_error applies after the last attempt",
+			task: "cmd-retry-ignore-error",
+			expected: []string{
+				"attempt 1\n",
+				"task: [cmd-retry-ignore-error] attempt 1/2 failed: exit status 1 - retrying",
+				"attempt 2\n",
+				"after ignored error",
+			},
+		},
+		{
+			name: "task call retried",
+			task: "task-call-retry",
+			expected: []string{
+				`task: [task-call-retry] attempt 1/3 failed: task: Failed to run task "flaky": exit status 1 - retrying`,
+				"flaky succeeded",
+			},
+		},
+		{
+			name: "task retried and defer runs once with the last attempt",
+			task: "task-retry",
+			expected: []string{
+				"task attempt 1\ntask: [task-retry] attempt 1/3 failed: exit status 1 - retrying\ntask attempt 2\ndeferred attempt 2 EXIT_CODE=\n",
+			},
+		},
+		{
+			name:         "task retries exhausted and defer gets last exit code",
+			task:         "task-retry-exhausted",
+			wantErr:      true,
+			wantExitCode: 4,
+			expected: []string{
+				"task attempt 1\ntask: [task-retry-exhausted] attempt 1/2 failed: exit status 4 - retrying\ntask attempt 2\ndeferred attempt 2 EXIT_CODE=4\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buff bytes.Buffer
+			e := task.NewExecutor(
+				task.WithDir(dir),
+				task.WithStdout(&buff),
+				task.WithStderr(&buff),
+				task.WithSilent(true),
+			)
+			require.NoError(t, e.Setup())
+
+			err := e.Run(t.Context(), &task.Call{Task: test.task})
+			if test.wantErr {
+				require.Error(t, err)
+				taskRunErr, ok := err.(*errors.TaskRunError)
+				require.True(t, ok, "cannot cast returned error to *task.TaskRunError")
+				assert.Equal(t, test.wantExitCode, taskRunErr.TaskExitCode())
+			} else {
+				require.NoError(t, err)
+			}
+			for _, s := range test.expected {
+				assert.Contains(t, buff.String(), s)
+			}
+			for _, s := range test.unexpected {
+				assert.NotContains(t, buff.String(), s)
+			}
+			if strings.HasPrefix(test.task, "task-retry") {
+				assert.Equal(t, 1, strings.Count(buff.String(), "deferred attempt"), "deferred cmd should run exactly once")
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	const dir = "testdata/timeout"
+
+	tests := []struct {
+		name        string
+		task        string
+		taskTimeout time.Duration
+		wantErr     string
+		expected    []string
+		unexpected  []string
+	}{
+		{
+			name:       "task timeout",
+			task:       "task-timeout",
+			wantErr:    `task: Task "task-timeout" timed out after 200ms`,
+			expected:   []string{"starting"},
+			unexpected: []string{"unreachable"},
+		},
+		{
+			name:       "cmd timeout",
+			task:       "cmd-timeout",
+			wantErr:    `task: Command "sleep 5" in task "cmd-timeout" timed out after 200ms`,
+			unexpected: []string{"unreachable"},
+		},
+		{
+			name:     "cmd timeout not reached",
+			task:     "cmd-timeout-not-reached",
+			expected: []string{"done"},
+		},
+		{
+			name:    "task call timeout",
+			task:    "task-call-timeout",
+			wantErr: `task: Command "task: slow" in task "task-call-timeout" timed out after 200ms`,
+		},
+		{
+			name:    "process tree is killed",
+			task:    "process-tree",
+			wantErr: `task: Task "process-tree" timed out after 200ms`,
+		},
+		{
+			name:       "timed out task is not retried",
+			task:       "not-retried",
+			wantErr:    `task: Task "not-retried" timed out after 200ms`,
+			unexpected: []string{"retrying"},
+		},
+		{
+			name:        "default task timeout",
+			task:        "default-timeout",
+			taskTimeout: 200 * time.Millisecond,
+			wantErr:     `task: Task "default-timeout" timed out after 200ms`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buff bytes.Buffer
+			e := task.NewExecutor(
+				task.WithDir(dir),
+				task.WithStdout(&buff),
+				task.WithStderr(&buff),
+				task.WithSilent(true),
+				task.WithTaskTimeout(test.taskTimeout),
+			)
+			require.NoError(t, e.Setup())
+
+			start := time.Now()
+			err := e.Run(t.Context(), &task.Call{Task: test.task})
+			assert.Less(t, time.Since(start), 2*time.Second, "commands should be killed on timeout")
+
+			if test.wantErr != "" {
+				require.Error(t, err)
+				var timeoutErr *errors.TaskTimeoutError
+				require.ErrorAs(t, err, &timeoutErr)
+				assert.Equal(t, test.wantErr, timeoutErr.Error())
+
+				taskRunErr, ok := err.(*errors.TaskRunError)
+				require.True(t, ok, "cannot cast returned error to *task.TaskRunError")
+				assert.Equal(t, errors.CodeTaskTimeout, taskRunErr.Code())
+			} else {
+				require.NoError(t, err)
+			}
+			for _, s := range test.expected {
+				assert.Contains(t, buff.String(), s)
+			}
+			for _, s := range test.unexpected {
+				assert.NotContains(t, buff.String(), s)
+			}
+		})
+	}
+}
+
 func TestExitCodeZero(t *testing.T) {
 	t.Parallel()
 
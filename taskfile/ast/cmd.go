@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"time"
+
 	"go.yaml.in/yaml/v3"
 
 	"github.com/go-task/task/v3/errors"
@@ -20,7 +22,10 @@ type Cmd struct {
 	Vars        *Vars
 	IgnoreError bool
 	Defer       bool
+	DeferOn     string
 	Platforms   []*Platform
+	Retries     *Retries
+	Timeout     time.Duration
 }
 
 func (c *Cmd) DeepCopy() *Cmd {
@@ -39,7 +44,10 @@ func (c *Cmd) DeepCopy() *Cmd {
 		Vars:        c.Vars.DeepCopy(),
 		IgnoreError: c.IgnoreError,
 		Defer:       c.Defer,
+		DeferOn:     c.DeferOn,
 		Platforms:   deepcopy.Slice(c.Platforms),
+		Retries:     c.Retries.DeepCopy(),
+		Timeout:     c.Timeout,
 	}
 }
 
@@ -68,6 +76,8 @@ func (c *Cmd) UnmarshalYAML(node *yaml.Node) error {
 			IgnoreError bool `yaml:"ignore_error"`
 			Defer       *Defer
 			Platforms   []*Platform
+			Retries     *Retries
+			Timeout     time.Duration
 		}
 		if err := node.Decode(&cmdStruct); err != nil {
 			return errors.NewTaskfileDecodeError(err, node)
@@ -77,6 +87,7 @@ func (c *Cmd) UnmarshalYAML(node *yaml.Node) error {
 			// A deferred command
 			if cmdStruct.Defer.Cmd != "" {
 				c.Defer = true
+				c.DeferOn = cmdStruct.Defer.On
 				c.Cmd = cmdStruct.Defer.Cmd
 				c.Silent = cmdStruct.Silent
 				return nil
@@ -85,6 +96,7 @@ func (c *Cmd) UnmarshalYAML(node *yaml.Node) error {
 			// A deferred task call
 			if cmdStruct.Defer.Task != "" {
 				c.Defer = true
+				c.DeferOn = cmdStruct.Defer.On
 				c.Task = cmdStruct.Defer.Task
 				c.Vars = cmdStruct.Defer.Vars
 				c.Silent = cmdStruct.Defer.Silent
@@ -101,6 +113,8 @@ func (c *Cmd) UnmarshalYAML(node *yaml.Node) error {
 			c.If = cmdStruct.If
 			c.Silent = cmdStruct.Silent
 			c.IgnoreError = cmdStruct.IgnoreError
+			c.Retries = cmdStruct.Retries
+			c.Timeout = cmdStruct.Timeout
 			return nil
 		}
 
@@ -115,6 +129,8 @@ func (c *Cmd) UnmarshalYAML(node *yaml.Node) error {
 			c.Sh = cmdStruct.Sh
 			c.IgnoreError = cmdStruct.IgnoreError
 			c.Platforms = cmdStruct.Platforms
+			c.Retries = cmdStruct.Retries
+			c.Timeout = cmdStruct.Timeout
 			return nil
 		}
 
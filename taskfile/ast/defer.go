@@ -6,11 +6,19 @@ import (
 	"github.com/go-task/task/v3/errors"
 )
 
+// The values of [Defer.On], telling when a deferred command runs.
+const (
+	DeferOnAlways  = "always"
+	DeferOnSuccess = "success"
+	DeferOnFailure = "failure"
+)
+
 type Defer struct {
 	Cmd    string
 	Task   string
 	Vars   *Vars
 	Silent bool
+	On     string
 }
 
 func (d *Defer) UnmarshalYAML(node *yaml.Node) error {
@@ -27,17 +35,31 @@ func (d *Defer) UnmarshalYAML(node *yaml.Node) error {
 	case yaml.MappingNode:
 		var deferStruct struct {
 			Defer  string
+			Cmd    string
 			Task   string
 			Vars   *Vars
 			Silent bool
+			On     string
 		}
 		if err := node.Decode(&deferStruct); err != nil {
 			return errors.NewTaskfileDecodeError(err, node)
 		}
+		switch deferStruct.On {
+		case "", DeferOnAlways, DeferOnSuccess, DeferOnFailure:
+		default:
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage(
+				"invalid defer on %q: must be %q, %q or %q",
+				deferStruct.On, DeferOnAlways, DeferOnSuccess, DeferOnFailure,
+			)
+		}
 		d.Cmd = deferStruct.Defer
+		if deferStruct.Cmd != "" {
+			d.Cmd = deferStruct.Cmd
+		}
 		d.Task = deferStruct.Task
 		d.Vars = deferStruct.Vars
 		d.Silent = deferStruct.Silent
+		d.On = deferStruct.On
 		return nil
 	}
 
@@ -0,0 +1,237 @@
+package ast
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/deepcopy"
+)
+
+// Extends is the list of tasks that a task extends. It can be given as a
+// single task name or as a list.
+type Extends []string
+
+func (e *Extends) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var str string
+		if err := node.Decode(&str); err != nil {
+			return errors.NewTaskfileDecodeError(err, node)
+		}
+		*e = []string{str}
+		return nil
+	case yaml.SequenceNode:
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return errors.NewTaskfileDecodeError(err, node)
+		}
+		*e = list
+		return nil
+	}
+	return errors.NewTaskfileDecodeError(nil, node).WithTypeMessage("extends")
+}
+
+// ResolveExtends merges the tasks that each task extends into it. It must be
+// called once every Taskfile is merged, so that tasks can extend tasks of
+// included Taskfiles.
+func (t *Tasks) ResolveExtends() error {
+	const (
+		resolving = iota + 1
+		resolved
+	)
+	state := make(map[string]int, t.Len())
+	var resolve func(task *Task, chain []string) error
+	resolve = func(task *Task, chain []string) error {
+		if task == nil || len(task.Extends) == 0 {
+			return nil
+		}
+		switch state[task.Task] {
+		case resolved:
+			return nil
+		case resolving:
+			return extendsError(task, "task %q extends itself: %s", task.Task, strings.Join(append(chain, task.Task), " -> "))
+		}
+		state[task.Task] = resolving
+		chain = append(chain, task.Task)
+
+		base := &Task{}
+		for _, name := range task.Extends {
+			parent, ok := t.Get(name)
+			if !ok {
+				return extendsError(task, "task %q extends %q, which does not exist", task.Task, name)
+			}
+			if err := resolve(parent, chain); err != nil {
+				return err
+			}
+			base.extend(parent)
+		}
+		base.extend(task)
+		task.inherit(base)
+		if task.Cache && (len(task.Sources) == 0 || len(task.Generates) == 0) {
+			return extendsError(task, "task with cache must have sources and generates")
+		}
+
+		state[task.Task] = resolved
+		return nil
+	}
+
+	for task := range t.Values(nil) {
+		if err := resolve(task, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extend merges the fields of the given task into t, as if t was extended by
+// it. Lists are appended, except for cmds and platforms which are replaced,
+// maps are merged and the other fields are replaced when they are set. The
+// vars of the include of the given task are merged as well, as its cmds may
+// use them.
+func (t *Task) extend(other *Task) {
+	if len(other.Cmds) > 0 {
+		t.Cmds = deepcopy.Slice(other.Cmds)
+	}
+	if len(other.Platforms) > 0 {
+		t.Platforms = deepcopy.Slice(other.Platforms)
+	}
+	t.Deps = append(t.Deps, deepcopy.Slice(other.Deps)...)
+	t.Sources = append(t.Sources, deepcopy.Slice(other.Sources)...)
+	t.Generates = append(t.Generates, deepcopy.Slice(other.Generates)...)
+	t.Status = append(t.Status, other.Status...)
+	t.Preconditions = append(t.Preconditions, deepcopy.Slice(other.Preconditions)...)
+	t.Set = append(t.Set, other.Set...)
+	t.Shopt = append(t.Shopt, other.Shopt...)
+	t.Dotenv = append(t.Dotenv, other.Dotenv...)
+	if other.Requires != nil {
+		if t.Requires == nil {
+			t.Requires = &Requires{}
+		}
+		for _, v := range other.Requires.Vars {
+			if !slices.ContainsFunc(t.Requires.Vars, func(r *VarsWithValidation) bool { return r.Name == v.Name }) {
+				t.Requires.Vars = append(t.Requires.Vars, v.DeepCopy())
+			}
+		}
+	}
+	if other.Vars != nil {
+		if t.Vars == nil {
+			t.Vars = NewVars()
+		}
+		t.Vars.Merge(other.Vars.DeepCopy(), nil)
+	}
+	if other.Env != nil {
+		if t.Env == nil {
+			t.Env = NewVars()
+		}
+		t.Env.Merge(other.Env.DeepCopy(), nil)
+	}
+	if other.IncludeVars != nil {
+		if t.IncludeVars == nil {
+			t.IncludeVars = NewVars()
+		}
+		t.IncludeVars.Merge(other.IncludeVars.DeepCopy(), nil)
+	}
+	if other.IncludedTaskfileVars != nil {
+		if t.IncludedTaskfileVars == nil {
+			t.IncludedTaskfileVars = NewVars()
+		}
+		t.IncludedTaskfileVars.Merge(other.IncludedTaskfileVars.DeepCopy(), nil)
+	}
+	if len(other.Prompt) > 0 {
+		t.Prompt = deepcopy.Slice(other.Prompt)
+	}
+	if len(other.Sh) > 0 {
+		t.Sh = append(ShArgs(nil), other.Sh...)
+	}
+	if other.Silent != nil {
+		t.Silent = deepcopy.Scalar(other.Silent)
+	}
+	if other.IgnoreFiles != nil {
+		t.IgnoreFiles = deepcopy.Scalar(other.IgnoreFiles)
+	}
+	if other.Retries != nil {
+		t.Retries = other.Retries.DeepCopy()
+	}
+	if other.Fingerprint != nil {
+		t.Fingerprint = other.Fingerprint.DeepCopy()
+	}
+	t.Method = cmp.Or(other.Method, t.Method)
+	t.Prefix = cmp.Or(other.Prefix, t.Prefix)
+	t.Run = cmp.Or(other.Run, t.Run)
+	t.If = cmp.Or(other.If, t.If)
+	if other.Timeout != 0 {
+		t.Timeout = other.Timeout
+	}
+	if other.boolsSet.interactive {
+		t.Interactive = other.Interactive
+		t.boolsSet.interactive = true
+	}
+	if other.boolsSet.ignoreError {
+		t.IgnoreError = other.IgnoreError
+		t.boolsSet.ignoreError = true
+	}
+	if other.boolsSet.watch {
+		t.Watch = other.Watch
+		t.boolsSet.watch = true
+	}
+	if other.boolsSet.failfast {
+		t.Failfast = other.Failfast
+		t.boolsSet.failfast = true
+	}
+	if other.boolsSet.cache {
+		t.Cache = other.Cache
+		t.boolsSet.cache = true
+	}
+}
+
+// inherit replaces the fields of t that can be inherited with the ones of the
+// given task. The fields that identify the task, like its name, description,
+// aliases, directory or location, are kept.
+func (t *Task) inherit(base *Task) {
+	t.Cmds = base.Cmds
+	t.Platforms = base.Platforms
+	t.Deps = base.Deps
+	t.Sources = base.Sources
+	t.Generates = base.Generates
+	t.Status = base.Status
+	t.Preconditions = base.Preconditions
+	t.Set = base.Set
+	t.Shopt = base.Shopt
+	t.Dotenv = base.Dotenv
+	t.Requires = base.Requires
+	t.Vars = base.Vars
+	t.Env = base.Env
+	t.Prompt = base.Prompt
+	t.Sh = base.Sh
+	t.Silent = base.Silent
+	t.IgnoreFiles = base.IgnoreFiles
+	t.Retries = base.Retries
+	t.Fingerprint = base.Fingerprint
+	t.Method = base.Method
+	t.Prefix = base.Prefix
+	t.Run = base.Run
+	t.If = base.If
+	t.Timeout = base.Timeout
+	t.Interactive = base.Interactive
+	t.IgnoreError = base.IgnoreError
+	t.Watch = base.Watch
+	t.Failfast = base.Failfast
+	t.Cache = base.Cache
+	t.boolsSet = base.boolsSet
+	t.IncludeVars = base.IncludeVars
+	t.IncludedTaskfileVars = base.IncludedTaskfileVars
+}
+
+func extendsError(task *Task, format string, a ...any) error {
+	err := &errors.TaskfileDecodeError{}
+	if task.Location != nil {
+		err.Location = task.Location.Taskfile
+		err.Line = task.Location.Line
+		err.Column = task.Location.Column
+	}
+	return err.WithMessage(format, a...)
+}
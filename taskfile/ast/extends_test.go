@@ -0,0 +1,150 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func TestResolveExtends(t *testing.T) {
+	t.Parallel()
+
+	const content = `
+version: '3'
+
+tasks:
+  base:
+    internal: true
+    desc: Base task
+    sources: ['go.mod']
+    env:
+      CGO_ENABLED: '0'
+      GOOS: linux
+    vars:
+      PKG: base
+    preconditions: ['test -f go.mod']
+    cmds: ['go build {{.PKG}}']
+
+  race:
+    internal: true
+    env:
+      CGO_ENABLED: '1'
+    vars:
+      FLAGS: -race
+
+  api:
+    extends: [base, race]
+    desc: Builds the API
+    sources: ['api/**/*.go']
+    vars:
+      PKG: ./api
+
+  web:
+    extends: base
+    silent: true
+    cmds: ['npm run build']
+`
+	var tf ast.Taskfile
+	require.NoError(t, yaml.Unmarshal([]byte(content), &tf))
+	require.NoError(t, tf.Tasks.ResolveExtends())
+
+	api, ok := tf.Tasks.Get("api")
+	require.True(t, ok)
+	// The fields identifying the task are not inherited
+	assert.Equal(t, "Builds the API", api.Desc)
+	assert.False(t, api.Internal)
+	// Lists are appended
+	assert.Equal(t, []*ast.Glob{{Glob: "go.mod"}, {Glob: "api/**/*.go"}}, api.Sources)
+	assert.Len(t, api.Preconditions, 1)
+	assert.Len(t, api.Cmds, 1)
+	// Maps are merged, later tasks taking precedence
+	assert.Equal(t, []string{"PKG", "FLAGS"}, keys(api.Vars))
+	pkg, _ := api.Vars.Get("PKG")
+	assert.Equal(t, "./api", pkg.Value)
+	assert.Equal(t, []string{"CGO_ENABLED", "GOOS"}, keys(api.Env))
+	cgo, _ := api.Env.Get("CGO_ENABLED")
+	assert.Equal(t, "1", cgo.Value)
+
+	// Cmds are replaced
+	web, ok := tf.Tasks.Get("web")
+	require.True(t, ok)
+	require.Len(t, web.Cmds, 1)
+	assert.Equal(t, "npm run build", web.Cmds[0].Cmd)
+	assert.True(t, web.IsSilent())
+
+	// The extended tasks are not modified
+	base, ok := tf.Tasks.Get("base")
+	require.True(t, ok)
+	assert.Len(t, base.Sources, 1)
+	pkg, _ = base.Vars.Get("PKG")
+	assert.Equal(t, "base", pkg.Value)
+}
+
+func TestResolveExtendsErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content string
+		message string
+		line    int
+	}{
+		"cycle": {
+			content: `
+version: '3'
+
+tasks:
+  a:
+    extends: b
+  b:
+    extends: [c]
+  c:
+    extends: a
+`,
+			message: `task "a" extends itself: a -> b -> c -> a`,
+			line:    5,
+		},
+		"missing": {
+			content: `
+version: '3'
+
+tasks:
+  a:
+    cmds: [echo a]
+  b:
+    extends: [a, missing]
+`,
+			message: `task "b" extends "missing", which does not exist`,
+			line:    7,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var tf ast.Taskfile
+			require.NoError(t, yaml.Unmarshal([]byte(test.content), &tf))
+			for task := range tf.Tasks.Values(nil) {
+				task.Location.Taskfile = "Taskfile.yml"
+			}
+			err := tf.Tasks.ResolveExtends()
+			var decodeErr *errors.TaskfileDecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, test.message, decodeErr.Message)
+			assert.Equal(t, "Taskfile.yml", decodeErr.Location)
+			assert.Equal(t, test.line, decodeErr.Line)
+		})
+	}
+}
+
+func keys(vars *ast.Vars) []string {
+	var keys []string
+	for key := range vars.Keys() {
+		keys = append(keys, key)
+	}
+	return keys
+}
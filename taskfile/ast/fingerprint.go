@@ -0,0 +1,57 @@
+package ast
+
+import (
+	"slices"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/go-task/task/v3/errors"
+)
+
+// Fingerprint selects what, besides the sources, is part of the fingerprint of
+// a task. Each combination of vars, env and cmds gets its own fingerprint, so
+// switching between them does not cause the task to run again.
+type Fingerprint struct {
+	// Vars lists the variables whose compiled values are part of the checksum.
+	Vars []string
+	// Env lists the environment variables whose values are part of the
+	// checksum.
+	Env []string
+	// Cmds adds the text of the commands of the task to the checksum.
+	Cmds bool
+	// Generates records the checksums of the generated files after each
+	// successful run. The task is not up-to-date if they changed since.
+	Generates bool
+}
+
+func (f *Fingerprint) DeepCopy() *Fingerprint {
+	if f == nil {
+		return nil
+	}
+	return &Fingerprint{
+		Vars:      slices.Clone(f.Vars),
+		Env:       slices.Clone(f.Env),
+		Cmds:      f.Cmds,
+		Generates: f.Generates,
+	}
+}
+
+func (f *Fingerprint) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return errors.NewTaskfileDecodeError(nil, node).WithTypeMessage("fingerprint")
+	}
+	var fingerprint struct {
+		Vars      []string
+		Env       []string
+		Cmds      bool
+		Generates bool
+	}
+	if err := node.Decode(&fingerprint); err != nil {
+		return errors.NewTaskfileDecodeError(err, node)
+	}
+	f.Vars = fingerprint.Vars
+	f.Env = fingerprint.Env
+	f.Cmds = fingerprint.Cmds
+	f.Generates = fingerprint.Generates
+	return nil
+}
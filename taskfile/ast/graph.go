@@ -116,5 +116,10 @@ func (tfg *TaskfileGraph) Merge() (*Taskfile, error) {
 		return nil, err
 	}
 
+	// Now that every task is merged, tasks can extend tasks of any namespace
+	if err := rootVertex.Taskfile.Tasks.ResolveExtends(); err != nil {
+		return nil, err
+	}
+
 	return rootVertex.Taskfile, nil
 }
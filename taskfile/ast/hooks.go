@@ -0,0 +1,139 @@
+package ast
+
+import (
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/deepcopy"
+	"github.com/go-task/task/v3/internal/filepathext"
+)
+
+// Hooks are commands run around the tasks of a Taskfile.
+type Hooks struct {
+	// BeforeAll and AfterAll are run once, before and after the tasks given
+	// to the executor.
+	BeforeAll []*Cmd
+	AfterAll  []*Cmd
+	// BeforeEach, AfterEach and OnFailure are run before and after each task,
+	// including dependencies and called tasks. OnFailure is only run when the
+	// task fails.
+	BeforeEach []*Cmd
+	AfterEach  []*Cmd
+	OnFailure  []*Cmd
+	// Populated during merging
+	Namespace            string
+	Dir                  string
+	Tasks                []string
+	Env                  *Vars
+	IncludeVars          *Vars
+	IncludedTaskfileVars *Vars
+}
+
+// AppliesTo returns true if the hooks apply to the given task. The hooks of
+// included Taskfiles only apply to the tasks of their namespace, or to the
+// tasks they define when the include is flattened.
+func (h *Hooks) AppliesTo(taskName string) bool {
+	if h.Tasks != nil {
+		return slices.Contains(h.Tasks, taskName)
+	}
+	return h.Namespace == "" || strings.HasPrefix(taskName, h.Namespace+NamespaceSeparator)
+}
+
+func (h *Hooks) DeepCopy() *Hooks {
+	if h == nil {
+		return nil
+	}
+	return &Hooks{
+		BeforeAll:            deepcopy.Slice(h.BeforeAll),
+		AfterAll:             deepcopy.Slice(h.AfterAll),
+		BeforeEach:           deepcopy.Slice(h.BeforeEach),
+		AfterEach:            deepcopy.Slice(h.AfterEach),
+		OnFailure:            deepcopy.Slice(h.OnFailure),
+		Namespace:            h.Namespace,
+		Dir:                  h.Dir,
+		Tasks:                slices.Clone(h.Tasks),
+		Env:                  h.Env.DeepCopy(),
+		IncludeVars:          h.IncludeVars.DeepCopy(),
+		IncludedTaskfileVars: h.IncludedTaskfileVars.DeepCopy(),
+	}
+}
+
+func (h *Hooks) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		var hooks struct {
+			BeforeAll  []*Cmd `yaml:"before_all"`
+			AfterAll   []*Cmd `yaml:"after_all"`
+			BeforeEach []*Cmd `yaml:"before_each"`
+			AfterEach  []*Cmd `yaml:"after_each"`
+			OnFailure  []*Cmd `yaml:"on_failure"`
+		}
+		if err := node.Decode(&hooks); err != nil {
+			return errors.NewTaskfileDecodeError(err, node)
+		}
+		for _, cmds := range [][]*Cmd{hooks.BeforeAll, hooks.AfterAll, hooks.BeforeEach, hooks.AfterEach, hooks.OnFailure} {
+			for _, cmd := range cmds {
+				if cmd != nil && (cmd.For != nil || cmd.Defer) {
+					return errors.NewTaskfileDecodeError(nil, node).WithMessage("hooks cannot use for or defer")
+				}
+			}
+		}
+		h.BeforeAll = hooks.BeforeAll
+		h.AfterAll = hooks.AfterAll
+		h.BeforeEach = hooks.BeforeEach
+		h.AfterEach = hooks.AfterEach
+		h.OnFailure = hooks.OnFailure
+		return nil
+	}
+
+	return errors.NewTaskfileDecodeError(nil, node).WithTypeMessage("hooks")
+}
+
+// mergeHooks returns the hooks of the given included Taskfile, scoped to the
+// namespace of the include, or to its tasks when it is flattened. The hooks
+// keep the env of the Taskfile that defines them, and the vars of its include,
+// like its tasks.
+func mergeHooks(t2 *Taskfile, include *Include, includedTaskfileVars *Vars) []*Hooks {
+	merged := make([]*Hooks, 0, len(t2.Hooks))
+	for _, h := range t2.Hooks {
+		h = h.DeepCopy()
+		if h.Env == nil {
+			h.Env = NewVars()
+			h.Env.Merge(t2.Env, nil)
+		}
+		if include.Flatten {
+			if h.Namespace == "" && h.Tasks == nil {
+				h.Tasks = slices.AppendSeq([]string{}, t2.Tasks.Keys(nil))
+			}
+		} else {
+			for _, cmds := range [][]*Cmd{h.BeforeAll, h.AfterAll, h.BeforeEach, h.AfterEach, h.OnFailure} {
+				for _, cmd := range cmds {
+					if cmd != nil && cmd.Task != "" {
+						cmd.Task = taskNameWithNamespace(cmd.Task, include.Namespace)
+					}
+				}
+			}
+			if h.Namespace == "" {
+				h.Namespace = include.Namespace
+			} else {
+				h.Namespace = taskNameWithNamespace(h.Namespace, include.Namespace)
+			}
+			for i, task := range h.Tasks {
+				h.Tasks[i] = taskNameWithNamespace(task, include.Namespace)
+			}
+		}
+		if include.AdvancedImport {
+			h.Dir = filepathext.SmartJoin(include.Dir, h.Dir)
+			if h.IncludeVars == nil {
+				h.IncludeVars = NewVars()
+			}
+			h.IncludeVars.Merge(include.Vars, nil)
+			h.IncludedTaskfileVars = includedTaskfileVars.DeepCopy()
+		}
+		merged = append(merged, h)
+	}
+	return merged
+}
@@ -18,6 +18,7 @@ type (
 		Taskfile       string
 		Dir            string
 		Optional       bool
+		If             string
 		Internal       bool
 		Aliases        []string
 		Excludes       []string
@@ -161,6 +162,7 @@ func (include *Include) UnmarshalYAML(node *yaml.Node) error {
 			Taskfile string
 			Dir      string
 			Optional bool
+			If       string
 			Internal bool
 			Flatten  bool
 			Aliases  []string
@@ -174,6 +176,7 @@ func (include *Include) UnmarshalYAML(node *yaml.Node) error {
 		include.Taskfile = includedTaskfile.Taskfile
 		include.Dir = includedTaskfile.Dir
 		include.Optional = includedTaskfile.Optional
+		include.If = includedTaskfile.If
 		include.Internal = includedTaskfile.Internal
 		include.Aliases = includedTaskfile.Aliases
 		include.Excludes = includedTaskfile.Excludes
@@ -198,6 +201,7 @@ func (include *Include) DeepCopy() *Include {
 		Taskfile:       include.Taskfile,
 		Dir:            include.Dir,
 		Optional:       include.Optional,
+		If:             include.If,
 		Internal:       include.Internal,
 		Excludes:       deepcopy.Slice(include.Excludes),
 		AdvancedImport: include.AdvancedImport,
@@ -4,6 +4,9 @@ type Location struct {
 	Line     int
 	Column   int
 	Taskfile string
+	// Commit is the commit of the git repository the Taskfile was read from,
+	// if any.
+	Commit string
 }
 
 func (l *Location) DeepCopy() *Location {
@@ -14,5 +17,6 @@ func (l *Location) DeepCopy() *Location {
 		Line:     l.Line,
 		Column:   l.Column,
 		Taskfile: l.Taskfile,
+		Commit:   l.Commit,
 	}
 }
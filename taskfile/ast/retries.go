@@ -0,0 +1,115 @@
+package ast
+
+import (
+	"slices"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/go-task/task/v3/errors"
+)
+
+const (
+	// BackoffConstant waits the same delay between every attempt.
+	BackoffConstant = "constant"
+	// BackoffExponential doubles the delay after every attempt.
+	BackoffExponential = "exponential"
+)
+
+// Retries describes how many times a failing task or command should be
+// attempted again and how long to wait between attempts.
+type Retries struct {
+	Count     int
+	Delay     time.Duration
+	MaxDelay  time.Duration
+	Backoff   string
+	ExitCodes []int
+}
+
+func (r *Retries) DeepCopy() *Retries {
+	if r == nil {
+		return nil
+	}
+	return &Retries{
+		Count:     r.Count,
+		Delay:     r.Delay,
+		MaxDelay:  r.MaxDelay,
+		Backoff:   r.Backoff,
+		ExitCodes: slices.Clone(r.ExitCodes),
+	}
+}
+
+// DelayFor returns how long to wait after the given (1-based) failed attempt
+// before starting the next one.
+func (r *Retries) DelayFor(attempt int) time.Duration {
+	if r == nil || r.Delay <= 0 {
+		return 0
+	}
+	delay := r.Delay
+	if r.Backoff == BackoffExponential {
+		for i := 1; i < attempt; i++ {
+			delay *= 2
+			if r.MaxDelay > 0 && delay >= r.MaxDelay {
+				break
+			}
+		}
+	}
+	if r.MaxDelay > 0 && delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	return delay
+}
+
+// ShouldRetryExitCode returns true if a failed attempt with the given exit code
+// is allowed to be retried. When no exit codes are listed, any non-zero exit
+// code is retried.
+func (r *Retries) ShouldRetryExitCode(code int) bool {
+	if r == nil {
+		return false
+	}
+	return len(r.ExitCodes) == 0 || slices.Contains(r.ExitCodes, code)
+}
+
+func (r *Retries) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+
+	case yaml.ScalarNode:
+		var count int
+		if err := node.Decode(&count); err != nil {
+			return errors.NewTaskfileDecodeError(err, node)
+		}
+		if count < 0 {
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage("retries count cannot be negative")
+		}
+		r.Count = count
+		return nil
+
+	case yaml.MappingNode:
+		var retries struct {
+			Count     int
+			Delay     time.Duration
+			MaxDelay  time.Duration `yaml:"max_delay"`
+			Backoff   string
+			ExitCodes []int `yaml:"exit_codes"`
+		}
+		if err := node.Decode(&retries); err != nil {
+			return errors.NewTaskfileDecodeError(err, node)
+		}
+		if retries.Count < 0 {
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage("retries count cannot be negative")
+		}
+		switch retries.Backoff {
+		case "", BackoffConstant, BackoffExponential:
+		default:
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage(`retries backoff must be "constant" or "exponential"`)
+		}
+		r.Count = retries.Count
+		r.Delay = retries.Delay
+		r.MaxDelay = retries.MaxDelay
+		r.Backoff = retries.Backoff
+		r.ExitCodes = retries.ExitCodes
+		return nil
+	}
+
+	return errors.NewTaskfileDecodeError(nil, node).WithTypeMessage("retries")
+}
@@ -0,0 +1,73 @@
+package ast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+func TestRetriesParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		content  string
+		v        any
+		expected any
+	}{
+		{
+			"3",
+			&ast.Retries{},
+			&ast.Retries{Count: 3},
+		},
+		{
+			`
+count: 2
+delay: 1s
+max_delay: 5s
+backoff: exponential
+exit_codes: [1, 75]
+`,
+			&ast.Retries{},
+			&ast.Retries{
+				Count:     2,
+				Delay:     time.Second,
+				MaxDelay:  5 * time.Second,
+				Backoff:   ast.BackoffExponential,
+				ExitCodes: []int{1, 75},
+			},
+		},
+	}
+	for _, test := range tests {
+		err := yaml.Unmarshal([]byte(test.content), test.v)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, test.v)
+	}
+
+	for _, content := range []string{"-1", "backoff: linear", "[1]"} {
+		err := yaml.Unmarshal([]byte(content), &ast.Retries{})
+		assert.Error(t, err, content)
+	}
+}
+
+func TestRetriesDelayFor(t *testing.T) {
+	t.Parallel()
+
+	constant := &ast.Retries{Count: 3, Delay: time.Second}
+	assert.Equal(t, time.Second, constant.DelayFor(1))
+	assert.Equal(t, time.Second, constant.DelayFor(3))
+
+	exponential := &ast.Retries{Count: 5, Delay: time.Second, MaxDelay: 5 * time.Second, Backoff: ast.BackoffExponential}
+	assert.Equal(t, time.Second, exponential.DelayFor(1))
+	assert.Equal(t, 2*time.Second, exponential.DelayFor(2))
+	assert.Equal(t, 4*time.Second, exponential.DelayFor(3))
+	assert.Equal(t, 5*time.Second, exponential.DelayFor(4))
+	assert.Equal(t, 5*time.Second, exponential.DelayFor(50))
+
+	var none *ast.Retries
+	assert.Equal(t, time.Duration(0), none.DelayFor(1))
+}
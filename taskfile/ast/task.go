@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"go.yaml.in/yaml/v3"
 
@@ -14,6 +15,7 @@ import (
 // Task represents a task
 type Task struct {
 	Task          string `hash:"ignore"`
+	Extends       Extends
 	Cmds          []*Cmd
 	Deps          []*Dep
 	Label         string
@@ -45,12 +47,29 @@ type Task struct {
 	Watch         bool
 	Location      *Location
 	Failfast      bool
+	Retries       *Retries
+	Timeout       time.Duration
+	Cache         bool
+	Fingerprint   *Fingerprint
+	IgnoreFiles   *bool
 	// Populated during merging
 	Namespace            string `hash:"ignore"`
 	IncludeVars          *Vars
 	IncludedTaskfileVars *Vars
 
 	FullName string `hash:"ignore"`
+
+	// boolsSet tracks the boolean fields set in the Taskfile, so that tasks
+	// extending others can set them back to false
+	boolsSet boolsSet
+}
+
+type boolsSet struct {
+	interactive bool
+	ignoreError bool
+	watch       bool
+	failfast    bool
+	cache       bool
 }
 
 func (t *Task) Name() string {
@@ -76,6 +95,12 @@ func (t *Task) IsSilent() bool {
 	return t.Silent != nil && *t.Silent
 }
 
+// UsesIgnoreFiles returns whether .gitignore and .taskignore files are used
+// when expanding the sources of the task. They are used unless disabled.
+func (t *Task) UsesIgnoreFiles() bool {
+	return t.IgnoreFiles == nil || *t.IgnoreFiles
+}
+
 // WildcardMatch will check if the given string matches the name of the Task and returns any wildcard values.
 func (t *Task) WildcardMatch(name string) (bool, []string) {
 	names := append([]string{t.Task}, t.Aliases...)
@@ -127,6 +152,7 @@ func (t *Task) UnmarshalYAML(node *yaml.Node) error {
 	// Full task object
 	case yaml.MappingNode:
 		var task struct {
+			Extends       Extends
 			Cmds          []*Cmd
 			Cmd           *Cmd
 			Deps          []*Dep
@@ -147,21 +173,31 @@ func (t *Task) UnmarshalYAML(node *yaml.Node) error {
 			Env           *Vars
 			Dotenv        []string
 			Silent        *bool `yaml:"silent,omitempty"`
-			Interactive   bool
+			Interactive   *bool
 			Internal      bool
 			Method        string
 			Prefix        string
-			IgnoreError   bool `yaml:"ignore_error"`
+			IgnoreError   *bool `yaml:"ignore_error"`
 			Run           string
 			Platforms     []*Platform
 			If            string
 			Requires      *Requires
-			Watch         bool
-			Failfast      bool
+			Watch         *bool
+			Failfast      *bool
+			Retries       *Retries
+			Timeout       time.Duration
+			Cache         *bool
+			Fingerprint   *Fingerprint
+			IgnoreFiles   *bool `yaml:"ignore_files"`
 		}
 		if err := node.Decode(&task); err != nil {
 			return errors.NewTaskfileDecodeError(err, node)
 		}
+		// Tasks that extend others are checked once their sources and generates
+		// are inherited
+		if task.Cache != nil && *task.Cache && len(task.Extends) == 0 && (len(task.Sources) == 0 || len(task.Generates) == 0) {
+			return errors.NewTaskfileDecodeError(nil, node).WithMessage("task with cache must have sources and generates")
+		}
 		if task.Cmd != nil {
 			if task.Cmds != nil {
 				return errors.NewTaskfileDecodeError(nil, node).WithMessage("task cannot have both cmd and cmds")
@@ -170,6 +206,7 @@ func (t *Task) UnmarshalYAML(node *yaml.Node) error {
 		} else {
 			t.Cmds = task.Cmds
 		}
+		t.Extends = task.Extends
 		t.Deps = task.Deps
 		t.Label = task.Label
 		t.Desc = task.Desc
@@ -188,17 +225,29 @@ func (t *Task) UnmarshalYAML(node *yaml.Node) error {
 		t.Env = task.Env
 		t.Dotenv = task.Dotenv
 		t.Silent = deepcopy.Scalar(task.Silent)
-		t.Interactive = task.Interactive
+		t.Interactive = task.Interactive != nil && *task.Interactive
 		t.Internal = task.Internal
 		t.Method = task.Method
 		t.Prefix = task.Prefix
-		t.IgnoreError = task.IgnoreError
+		t.IgnoreError = task.IgnoreError != nil && *task.IgnoreError
 		t.Run = task.Run
 		t.Platforms = task.Platforms
 		t.If = task.If
 		t.Requires = task.Requires
-		t.Watch = task.Watch
-		t.Failfast = task.Failfast
+		t.Watch = task.Watch != nil && *task.Watch
+		t.Failfast = task.Failfast != nil && *task.Failfast
+		t.Retries = task.Retries
+		t.Timeout = task.Timeout
+		t.Cache = task.Cache != nil && *task.Cache
+		t.boolsSet = boolsSet{
+			interactive: task.Interactive != nil,
+			ignoreError: task.IgnoreError != nil,
+			watch:       task.Watch != nil,
+			failfast:    task.Failfast != nil,
+			cache:       task.Cache != nil,
+		}
+		t.Fingerprint = task.Fingerprint
+		t.IgnoreFiles = deepcopy.Scalar(task.IgnoreFiles)
 		return nil
 	}
 
@@ -213,6 +262,7 @@ func (t *Task) DeepCopy() *Task {
 	}
 	c := &Task{
 		Task:                 t.Task,
+		Extends:              deepcopy.Slice(t.Extends),
 		Cmds:                 deepcopy.Slice(t.Cmds),
 		Deps:                 deepcopy.Slice(t.Deps),
 		Label:                t.Label,
@@ -248,6 +298,12 @@ func (t *Task) DeepCopy() *Task {
 		FullName:             t.FullName,
 		Watch:                t.Watch,
 		Failfast:             t.Failfast,
+		Retries:              t.Retries.DeepCopy(),
+		Timeout:              t.Timeout,
+		Cache:                t.Cache,
+		Fingerprint:          t.Fingerprint.DeepCopy(),
+		IgnoreFiles:          deepcopy.Scalar(t.IgnoreFiles),
+		boolsSet:             t.boolsSet,
 	}
 	return c
 }
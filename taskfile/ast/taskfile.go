@@ -35,6 +35,9 @@ type Taskfile struct {
 	Dotenv   []string
 	Run      string
 	Interval time.Duration
+	// Hooks lists the hooks of the Taskfile, followed by the ones of the
+	// included Taskfiles
+	Hooks []*Hooks
 }
 
 // Merge merges the second Taskfile into the first
@@ -70,6 +73,7 @@ func (t1 *Taskfile) Merge(t2 *Taskfile, include *Include) error {
 	}
 	t1.Vars.Merge(t2.Vars, include)
 	t1.Env.Merge(t2.Env, include)
+	t1.Hooks = append(t1.Hooks, mergeHooks(t2, include, t1.Vars)...)
 	return t1.Tasks.Merge(t2.Tasks, include, t1.Vars)
 }
 
@@ -91,6 +95,7 @@ func (tf *Taskfile) UnmarshalYAML(node *yaml.Node) error {
 			Dotenv   []string
 			Run      string
 			Interval time.Duration
+			Hooks    *Hooks
 		}
 		if err := node.Decode(&taskfile); err != nil {
 			return errors.NewTaskfileDecodeError(err, node)
@@ -109,6 +114,9 @@ func (tf *Taskfile) UnmarshalYAML(node *yaml.Node) error {
 		tf.Dotenv = taskfile.Dotenv
 		tf.Run = taskfile.Run
 		tf.Interval = taskfile.Interval
+		if taskfile.Hooks != nil {
+			tf.Hooks = []*Hooks{taskfile.Hooks}
+		}
 		if tf.Includes == nil {
 			tf.Includes = NewIncludes()
 		}
@@ -24,6 +24,7 @@ vars:
 `
 		yamlDeferredCall = `defer: { task: some_task, vars: { PARAM1: "var" } }`
 		yamlDeferredCmd  = `defer: echo 'test'`
+		yamlDeferredOn   = `defer: { cmd: echo 'test', on: failure }`
 	)
 	tests := []struct {
 		content  string
@@ -61,6 +62,11 @@ vars:
 			&ast.Cmd{},
 			&ast.Cmd{Cmd: "echo 'test'", Defer: true},
 		},
+		{
+			yamlDeferredOn,
+			&ast.Cmd{},
+			&ast.Cmd{Cmd: "echo 'test'", Defer: true, DeferOn: ast.DeferOnFailure},
+		},
 		{
 			yamlDeferredCall,
 			&ast.Cmd{},
@@ -110,3 +116,11 @@ vars:
 		assert.Equal(t, test.expected, test.v)
 	}
 }
+
+func TestDeferOnParseError(t *testing.T) {
+	t.Parallel()
+
+	var cmd ast.Cmd
+	err := yaml.Unmarshal([]byte(`defer: { cmd: echo 'test', on: error }`), &cmd)
+	require.ErrorContains(t, err, `invalid defer on "error": must be "always", "success" or "failure"`)
+}
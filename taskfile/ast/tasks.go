@@ -143,6 +143,11 @@ func (t1 *Tasks) Merge(t2 *Tasks, include *Include, includedTaskfileVars *Vars)
 				}
 			}
 
+			// Add namespaces to the extended tasks
+			for i, name := range task.Extends {
+				task.Extends[i] = taskNameWithNamespace(name, include.Namespace)
+			}
+
 			// Add namespaces to task commands
 			for _, cmd := range task.Cmds {
 				if cmd != nil && cmd.Task != "" {
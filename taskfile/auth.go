@@ -0,0 +1,134 @@
+package taskfile
+
+import (
+	"cmp"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/bgentry/go-netrc/netrc"
+)
+
+// Credentials are used to authenticate to the host of remote Taskfiles.
+type Credentials struct {
+	// Username and Password are sent using basic authentication.
+	Username string
+	Password string
+	// Token is sent as a bearer token. Git doesn't support bearer tokens, so it
+	// is sent as the password of git Taskfiles instead.
+	Token string
+	// Headers are added to the requests of HTTP Taskfiles. They are not sent to
+	// git servers.
+	Headers map[string]string
+}
+
+// WithCredentials sets the credentials of the hosts of remote Taskfiles, keyed
+// by host, optionally followed by a port.
+func WithCredentials(credentials map[string]Credentials) NodeOption {
+	return func(node *baseNode) {
+		node.credentials = credentials
+	}
+}
+
+// credentialsFor returns the credentials configured for the host of the given
+// URL, if any. Credentials configured for the host and its port take
+// precedence over the ones configured for the host alone.
+func credentialsFor(credentials map[string]Credentials, u *url.URL) (Credentials, bool) {
+	if c, ok := credentials[u.Host]; ok {
+		return c, true
+	}
+	c, ok := credentials[u.Hostname()]
+	return c, ok
+}
+
+// gitConfig returns the credentials in the form of the git configuration of
+// the repositories starting with the given URL, as key and value pairs. A
+// token or a password is sent in a basic authorization header, as git doesn't
+// support bearer tokens.
+func (c Credentials) gitConfig(prefix string) []string {
+	if c.Token != "" {
+		return []string{"http." + prefix + ".extraHeader", basicAuthorization(cmp.Or(c.Username, "x-access-token"), c.Token)}
+	}
+	if c.Password != "" {
+		return []string{"http." + prefix + ".extraHeader", basicAuthorization(c.Username, c.Password)}
+	}
+	if c.Username != "" {
+		return []string{"credential." + prefix + ".username", c.Username}
+	}
+	return nil
+}
+
+func basicAuthorization(username, password string) string {
+	return "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// authTransport adds the credentials of its host to each request. The
+// credentials are looked up for each request, so redirects to other hosts
+// don't receive them.
+type authTransport struct {
+	base        http.RoundTripper
+	credentials map[string]Credentials
+	netrc       *netrc.Netrc
+}
+
+// withCredentials returns a copy of the given client that authenticates its
+// requests with the given credentials, or the ones found in the .netrc file.
+func withCredentials(client *http.Client, credentials map[string]Credentials) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	authClient := *client
+	authClient.Transport = &authTransport{
+		base:        base,
+		credentials: credentials,
+		netrc:       loadNetrc(),
+	}
+	return &authClient
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c, ok := credentialsFor(t.credentials, req.URL); ok {
+		req = req.Clone(req.Context())
+		switch {
+		case c.Token != "":
+			req.Header.Set("Authorization", "Bearer "+c.Token)
+		case c.Username != "" || c.Password != "":
+			req.SetBasicAuth(c.Username, c.Password)
+		}
+		for name, value := range c.Headers {
+			req.Header.Set(name, value)
+		}
+	} else if t.netrc != nil && req.URL.User == nil && req.Header.Get("Authorization") == "" {
+		if m := t.netrc.FindMachine(req.URL.Hostname()); m != nil {
+			req = req.Clone(req.Context())
+			req.SetBasicAuth(m.Login, m.Password)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// loadNetrc parses the .netrc file of the user, or the one set by the NETRC
+// environment variable. It returns nil if there is none or it is invalid.
+func loadNetrc() *netrc.Netrc {
+	path := os.Getenv("NETRC")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		name := ".netrc"
+		if runtime.GOOS == "windows" {
+			name = "_netrc"
+		}
+		path = filepath.Join(home, name)
+	}
+	n, err := netrc.ParseFile(path)
+	if err != nil {
+		return nil
+	}
+	return n
+}
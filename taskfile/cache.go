@@ -0,0 +1,241 @@
+package taskfile
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-task/task/v3/errors"
+)
+
+// CacheEntryKind is the kind of a [CacheEntry].
+type CacheEntryKind string
+
+const (
+	// CacheEntryRemote is a remote Taskfile, downloaded over HTTP or read from
+	// a git repository.
+	CacheEntryRemote CacheEntryKind = "remote"
+	// CacheEntryGit is a clone of a git repository.
+	CacheEntryGit CacheEntryKind = "git"
+	// CacheEntryArchive is an extracted archive.
+	CacheEntryArchive CacheEntryKind = "archive"
+)
+
+// A CacheEntry is a remote Taskfile, a git repository or an archive stored in
+// the cache.
+type CacheEntry struct {
+	Kind CacheEntryKind
+	// URL is the location of the remote Taskfile, the URL of the git repository
+	// or of the archive. It is empty for remote Taskfiles cached by older
+	// versions of Task.
+	URL string
+	// Path is the path of the cached Taskfile, of the git repository or of the
+	// extracted archive.
+	Path string
+	// Timestamp is the last time the entry was downloaded.
+	Timestamp time.Time
+	// Size is the size of the entry, in bytes.
+	Size int64
+	// Checksum is the checksum of the remote Taskfile or of the archive, or
+	// the commit checked out in the git repository.
+	Checksum string
+	// files lists the files of the entry, which are removed when it is pruned
+	files []string
+}
+
+// ListCache returns the remote Taskfiles cached in the given directory, the
+// cloned git repositories and the extracted archives, sorted by kind and URL.
+func ListCache(ctx context.Context, dir string) ([]*CacheEntry, error) {
+	entries, err := listRemoteCache(filepath.Join(dir, remoteCacheDir))
+	if err != nil {
+		return nil, err
+	}
+	gitEntries, err := listGitCache(ctx, filepath.Join(dir, gitCacheDirName))
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, gitEntries...)
+	archiveEntries, err := listArchiveCache(filepath.Join(dir, archiveCacheDirName))
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, archiveEntries...)
+	slices.SortFunc(entries, func(a, b *CacheEntry) int {
+		return strings.Compare(string(a.Kind)+a.URL+a.Path, string(b.Kind)+b.URL+b.Path)
+	})
+	return entries, nil
+}
+
+// PruneCache removes the entries of the cache that were downloaded before the
+// given expiry duration, and returns them.
+func PruneCache(ctx context.Context, dir string, expiry time.Duration) ([]*CacheEntry, error) {
+	entries, err := ListCache(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	var pruned []*CacheEntry
+	for _, entry := range entries {
+		if time.Since(entry.Timestamp) < expiry {
+			continue
+		}
+		for _, file := range entry.files {
+			if err := os.RemoveAll(file); err != nil {
+				return pruned, err
+			}
+		}
+		pruned = append(pruned, entry)
+	}
+	return pruned, nil
+}
+
+// ClearCache removes every remote Taskfile, git repository and archive cached in
+// the given directory.
+func ClearCache(dir string) error {
+	for _, name := range []string{remoteCacheDir, gitCacheDirName, archiveCacheDirName} {
+		if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return CleanGitCache()
+}
+
+func listRemoteCache(dir string) ([]*CacheEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*CacheEntry, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		base := strings.TrimSuffix(path, ".yaml")
+		entry := &CacheEntry{
+			Kind:      CacheEntryRemote,
+			Path:      path,
+			Timestamp: info.ModTime(),
+			Size:      info.Size(),
+			files:     []string{path},
+		}
+		for _, suffix := range []string{"checksum", "timestamp", "url", "commit", "sig"} {
+			b, err := os.ReadFile(base + "." + suffix)
+			if err != nil {
+				continue
+			}
+			entry.files = append(entry.files, base+"."+suffix)
+			switch suffix {
+			case "checksum":
+				entry.Checksum = string(b)
+			case "timestamp":
+				if timestamp, err := time.Parse(time.RFC3339, string(b)); err == nil {
+					entry.Timestamp = timestamp
+				}
+			case "url":
+				entry.URL = string(b)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func listGitCache(ctx context.Context, dir string) ([]*CacheEntry, error) {
+	var entries []*CacheEntry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == dir {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		gitDir := filepath.Join(path, ".git")
+		info, err := os.Stat(gitDir)
+		if err != nil {
+			return nil
+		}
+		entry := &CacheEntry{
+			Kind:      CacheEntryGit,
+			Path:      path,
+			Timestamp: readGitTimestamp(path),
+			files:     []string{path},
+		}
+		if entry.Timestamp.IsZero() {
+			entry.Timestamp = info.ModTime()
+		}
+		entry.URL, _ = gitOutput(ctx, path, "remote", "get-url", "origin")
+		entry.Checksum, _ = gitOutput(ctx, path, "rev-parse", "HEAD")
+		entry.Size, err = dirSize(path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return filepath.SkipDir
+	})
+	return entries, err
+}
+
+func listArchiveCache(dir string) ([]*CacheEntry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.url"))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*CacheEntry, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		url, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		base := strings.TrimSuffix(path, ".url")
+		checksum, _ := os.ReadFile(base + ".checksum")
+		size, err := dirSize(base)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		entries = append(entries, &CacheEntry{
+			Kind:      CacheEntryArchive,
+			URL:       string(url),
+			Path:      base,
+			Timestamp: info.ModTime(),
+			Size:      size,
+			Checksum:  string(checksum),
+			files:     []string{base, path, base + ".checksum"},
+		})
+	}
+	return entries, nil
+}
+
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
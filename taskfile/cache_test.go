@@ -0,0 +1,68 @@
+package taskfile
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeEntry := func(key, url string, timestamp time.Time) {
+		node := &CacheNode{baseNode: &baseNode{dir: filepath.Join(dir, remoteCacheDir)}}
+		require.NoError(t, node.CreateCacheDir())
+		base := filepath.Join(dir, remoteCacheDir, key)
+		require.NoError(t, os.WriteFile(base+".yaml", []byte("version: '3'\n"), 0o644))
+		require.NoError(t, os.WriteFile(base+".checksum", []byte("sum-"+key), 0o644))
+		require.NoError(t, os.WriteFile(base+".timestamp", []byte(timestamp.Format(time.RFC3339)), 0o644))
+		require.NoError(t, os.WriteFile(base+".url", []byte(url), 0o644))
+	}
+	writeEntry("old", "https://example.com/old.yml", time.Now().Add(-2*time.Hour))
+	writeEntry("new", "https://example.com/new.yml", time.Now())
+
+	repo := filepath.Join(dir, gitCacheDirName, "example.com", "repo.git", "_default_")
+	require.NoError(t, os.MkdirAll(repo, 0o755))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", "https://example.com/repo.git"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		require.NoError(t, cmd.Run())
+	}
+
+	entries, err := ListCache(t.Context(), dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, CacheEntryGit, entries[0].Kind)
+	assert.Equal(t, "https://example.com/repo.git", entries[0].URL)
+	assert.Positive(t, entries[0].Size)
+	assert.Equal(t, CacheEntryRemote, entries[1].Kind)
+	assert.Equal(t, "https://example.com/new.yml", entries[1].URL)
+	assert.Equal(t, "sum-new", entries[1].Checksum)
+	assert.Equal(t, int64(len("version: '3'\n")), entries[1].Size)
+	assert.Equal(t, "https://example.com/old.yml", entries[2].URL)
+
+	pruned, err := PruneCache(t.Context(), dir, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, "https://example.com/old.yml", pruned[0].URL)
+	assert.NoFileExists(t, filepath.Join(dir, remoteCacheDir, "old.checksum"))
+
+	entries, err = ListCache(t.Context(), dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	require.NoError(t, ClearCache(dir))
+	entries, err = ListCache(t.Context(), dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,101 @@
+package taskfile
+
+import (
+	"os"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/go-task/task/v3/errors"
+)
+
+// LockfileName is the name of the file that pins the remote Taskfiles included
+// by a Taskfile. It is stored next to the root Taskfile.
+const LockfileName = "Taskfile.lock"
+
+const lockfileHeader = "# This file is generated by \"task --lock\". Do not edit it manually.\n"
+
+// LockMode defines how a [Reader] uses the lock file.
+type LockMode int
+
+const (
+	// LockModeVerify verifies remote Taskfiles against the lock file, if it
+	// exists. Remote Taskfiles that are not in it are rejected.
+	LockModeVerify LockMode = iota
+	// LockModeWrite verifies remote Taskfiles against the lock file, adds the
+	// missing ones and removes the ones that are not included anymore.
+	LockModeWrite
+	// LockModeUpdate ignores the content of the lock file and writes a new one
+	// from the latest version of every remote Taskfile.
+	LockModeUpdate
+)
+
+// A Lockfile records what each remote Taskfile resolved to, so that later
+// reads can detect when it changed.
+type Lockfile struct {
+	Version int                   `yaml:"version"`
+	Remotes map[string]*LockEntry `yaml:"remotes"`
+}
+
+// A LockEntry is the locked state of a single remote Taskfile, keyed by its
+// location in the [Lockfile].
+type LockEntry struct {
+	// URL is the URL the Taskfile was downloaded from, or the URL of the
+	// repository for git Taskfiles.
+	URL string `yaml:"url"`
+	// Commit is the SHA of the commit git Taskfiles were read from.
+	Commit string `yaml:"commit,omitempty"`
+	// Checksum is the SHA-256 checksum of the content of the Taskfile.
+	Checksum string `yaml:"checksum"`
+}
+
+func newLockfile() *Lockfile {
+	return &Lockfile{
+		Version: 1,
+		Remotes: map[string]*LockEntry{},
+	}
+}
+
+// readLockfile reads the lock file at the given path. It returns nil if the
+// file doesn't exist.
+func readLockfile(path string) (*Lockfile, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lock := newLockfile()
+	if err := yaml.Unmarshal(b, lock); err != nil {
+		return nil, &errors.TaskfileInvalidError{URI: path, Err: err}
+	}
+	if lock.Remotes == nil {
+		lock.Remotes = map[string]*LockEntry{}
+	}
+	return lock, nil
+}
+
+// write writes the lock file to the given path.
+func (lock *Lockfile) write(path string) error {
+	b, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(lockfileHeader), b...), 0o644)
+}
+
+// newLockEntry returns the entry recording the current state of the given
+// remote node, whose content has the given checksum.
+func newLockEntry(node RemoteNode, checksum string) *LockEntry {
+	entry := &LockEntry{Checksum: checksum}
+	switch node := node.(type) {
+	case *HTTPNode:
+		entry.URL = node.ResolvedURL()
+	case *GitNode:
+		entry.URL = node.ResolvedURL()
+		entry.Commit = node.Commit()
+	case *ArchiveNode:
+		entry.URL = node.ResolvedURL()
+	}
+	return entry
+}
@@ -26,6 +26,9 @@ type Node interface {
 type RemoteNode interface {
 	Node
 	ReadContext(ctx context.Context) ([]byte, error)
+	// ReadSignature returns the detached signature of the Taskfile, or nil if
+	// it is not signed. It must be called after ReadContext.
+	ReadSignature(ctx context.Context) ([]byte, error)
 	CacheKey() string
 }
 
@@ -61,6 +64,8 @@ func NewNode(
 	switch scheme {
 	case "git":
 		node, err = NewGitNode(entrypoint, dir, insecure, opts...)
+	case "archive":
+		node, err = NewArchiveNode(entrypoint, dir, insecure, opts...)
 	case "http", "https":
 		node, err = NewHTTPNode(entrypoint, dir, insecure, opts...)
 	default:
@@ -78,12 +83,18 @@ func isRemoteEntrypoint(entrypoint string) bool {
 	switch scheme {
 	case "git", "http", "https":
 		return true
+	case "archive":
+		return strings.Contains(entrypoint, "://")
 	default:
 		return false
 	}
 }
 
 func getScheme(uri string) (string, error) {
+	if archiveFormat(uri) != "" {
+		return "archive", nil
+	}
+
 	u, err := giturls.Parse(uri)
 	if u == nil {
 		return "", err
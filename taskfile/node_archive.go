@@ -0,0 +1,497 @@
+package taskfile
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-getter"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/fsext"
+)
+
+// An ArchiveNode is a node that reads a Taskfile from a .tar.gz or .zip
+// archive, downloaded via HTTP or read from the local filesystem. The archive
+// is extracted into the remote cache, so that its tasks can use the other
+// files it contains.
+type ArchiveNode struct {
+	*baseNode
+	// archive is the URL of the archive, or its absolute path if it is local
+	archive string
+	// url is the URL of the archive, which is nil if it is local
+	url *url.URL
+	// source is the URL the archive is downloaded from, which differs from url
+	// when it is mirrored
+	source *url.URL
+	client *http.Client
+	// path is the path of the Taskfile in the archive, which is searched for
+	// if empty
+	path string
+	// resolved is the URL the archive was actually downloaded from, once read
+	resolved *url.URL
+	// file is the path of the Taskfile in the extracted archive, once resolved
+	file string
+	// cacheDir is the directory the archive is extracted into, which is a
+	// temporary directory if empty
+	cacheDir string
+	// vendorDir is the directory of the vendored copy of the extracted
+	// archive, if the Taskfile is vendored
+	vendorDir string
+}
+
+// archiveCacheDirName is the directory of the extracted archives in the remote
+// cache
+const archiveCacheDirName = "archive"
+
+// archiveDecompressors are the decompressors of the supported archive formats,
+// keyed by extension. They reject entries outside of the archive.
+var archiveDecompressors = map[string]getter.Decompressor{
+	".tar.gz": new(getter.TarGzipDecompressor),
+	".tgz":    new(getter.TarGzipDecompressor),
+	".zip":    new(getter.ZipDecompressor),
+}
+
+// The extractions are synchronized like the git clones, so that each archive is
+// extracted at most once until the cache is cleaned.
+var globalArchiveCache = &gitRepoCache{
+	locks:   make(map[string]*sync.Mutex),
+	fetched: make(map[string]bool),
+}
+
+// cleanArchiveCache resets the state of the extracted archives, so that they
+// are extracted again by the next read. Unlike git clones, extracted archives
+// are kept, as the tasks use their files.
+func cleanArchiveCache() {
+	globalArchiveCache.mu.Lock()
+	globalArchiveCache.locks = make(map[string]*sync.Mutex)
+	globalArchiveCache.fetched = make(map[string]bool)
+	globalArchiveCache.mu.Unlock()
+}
+
+// tempArchiveCacheDir returns the directory archives are extracted into when no
+// cache directory is set.
+func tempArchiveCacheDir() string {
+	return filepath.Join(os.TempDir(), "task-archives")
+}
+
+func NewArchiveNode(
+	entrypoint string,
+	dir string,
+	insecure bool,
+	opts ...NodeOption,
+) (*ArchiveNode, error) {
+	base := NewBaseNode(dir, opts...)
+
+	// Local archives
+	if !strings.Contains(entrypoint, "://") {
+		archive, path := splitArchiveEntrypoint(entrypoint)
+		archive, err := execext.ExpandLiteral(archive)
+		if err != nil {
+			return nil, err
+		}
+		if archive, err = filepath.Abs(archive); err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(archive); err != nil {
+			return nil, errors.TaskfileNotFoundError{URI: entrypoint}
+		}
+		return &ArchiveNode{
+			baseNode: base,
+			archive:  archive,
+			path:     path,
+		}, nil
+	}
+
+	u, err := url.Parse(entrypoint)
+	if err != nil {
+		return nil, err
+	}
+	archivePath, path := splitURLOnDoubleSlash(u)
+	u.Path, u.RawPath = archivePath, ""
+	source, err := url.Parse(base.mirror(entrypoint))
+	if err != nil {
+		return nil, err
+	}
+	source.Path, _ = splitURLOnDoubleSlash(source)
+	source.RawPath = ""
+	if (u.Scheme == "http" || source.Scheme == "http") && !insecure {
+		return nil, &errors.TaskfileNotSecureError{URI: source.Redacted()}
+	}
+
+	client, err := buildHTTPClient(insecure, base.caCert, base.cert, base.certKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArchiveNode{
+		baseNode: base,
+		archive:  u.Redacted(),
+		url:      u,
+		source:   source,
+		client:   withCredentials(client, base.credentials),
+		path:     path,
+	}, nil
+}
+
+func (node *ArchiveNode) Location() string {
+	return joinArchiveEntrypoint(node.archive, node.path)
+}
+
+// local reports whether the archive is read from the local filesystem.
+func (node *ArchiveNode) local() bool {
+	return node.url == nil
+}
+
+func (node *ArchiveNode) Read() ([]byte, error) {
+	return node.ReadContext(context.Background())
+}
+
+// ReadContext extracts the archive, unless it was already extracted since the
+// cache was cleaned, and reads the Taskfile inside it.
+func (node *ArchiveNode) ReadContext(ctx context.Context) ([]byte, error) {
+	if err := node.extract(ctx); err != nil {
+		return nil, err
+	}
+	node.file = ""
+	file, err := node.resolveFile()
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(file)
+}
+
+// ReadSignature returns the signature stored next to the Taskfile in the
+// archive, if any.
+func (node *ArchiveNode) ReadSignature(ctx context.Context) ([]byte, error) {
+	file, err := node.resolveFile()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(file + SignatureExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// archiveChecksum returns the checksum of the archive itself, which is stored
+// when it is extracted.
+func (node *ArchiveNode) archiveChecksum() (string, error) {
+	b, err := os.ReadFile(node.extractDir() + ".checksum")
+	if err != nil {
+		return "", fmt.Errorf("task: %q was not extracted: %w", node.Location(), err)
+	}
+	return string(b), nil
+}
+
+// setCacheDir sets the directory the archive is extracted into.
+func (node *ArchiveNode) setCacheDir(dir string) {
+	node.cacheDir = dir
+}
+
+// extractDir returns the directory the archive is extracted into, or the
+// directory of its vendored copy. It only depends on the URL of the archive, so
+// that the Taskfiles of an archive share the same copy.
+func (node *ArchiveNode) extractDir() string {
+	if node.vendorDir != "" {
+		return node.vendorDir
+	}
+	baseDir := tempArchiveCacheDir()
+	if node.cacheDir != "" {
+		baseDir = node.cacheDir
+	}
+	name := path.Base(filepath.ToSlash(node.archive))
+	if node.url != nil {
+		name = path.Base(node.url.Path)
+	}
+	key := strings.TrimRight(checksum([]byte(node.archive)), "=")[:16]
+	return filepath.Join(baseDir, fmt.Sprintf("%s.%s", name, key))
+}
+
+// useVendored makes the node use the vendored copy of the extracted archive in
+// the given directory instead of extracting it.
+func (node *ArchiveNode) useVendored(dir string) error {
+	if _, err := os.Stat(dir + ".checksum"); err != nil {
+		return err
+	}
+	node.vendorDir = dir
+	node.file = ""
+	_, err := node.resolveFile()
+	return err
+}
+
+// writeFiles copies the extracted archive into the given directory, along with
+// the checksum of the archive, to vendor it.
+func (node *ArchiveNode) writeFiles(dir string) error {
+	if node.vendorDir != "" {
+		return fmt.Errorf("task: %q was not extracted", node.Location())
+	}
+	checksum, err := node.archiveChecksum()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.CopyFS(dir, os.DirFS(node.extractDir())); err != nil {
+		return err
+	}
+	return os.WriteFile(dir+".checksum", []byte(checksum), 0o644)
+}
+
+// extracted reports whether the archive is extracted, which is required to use
+// its cached Taskfile.
+func (node *ArchiveNode) extracted() bool {
+	_, err := os.Stat(node.extractDir())
+	return err == nil
+}
+
+// extract downloads or reads the archive and extracts it. The previous copy is
+// only replaced once the archive is fully extracted.
+func (node *ArchiveNode) extract(ctx context.Context) error {
+	dir := node.extractDir()
+
+	mutex := globalArchiveCache.getLockForRepo(dir)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if globalArchiveCache.markFetched(dir) && node.extracted() {
+		return nil
+	}
+
+	b, err := node.readArchive(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(dir), filepath.Base(dir)+".*"+archiveFormat(node.archive))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	decompressor := archiveDecompressors[archiveFormat(node.archive)]
+	if err := decompressor.Decompress(tmpDir, f.Name(), true, 0); err != nil {
+		return fmt.Errorf("task: Failed to extract %q: %w", node.archive, err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return err
+	}
+
+	// Store the URL and the checksum of the archive, as they can't be derived
+	// from the directory
+	if err := os.WriteFile(dir+".checksum", []byte(checksum(b)), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(dir+".url", []byte(node.archive), 0o644)
+}
+
+// readArchive returns the content of the archive, downloading it if it is
+// remote.
+func (node *ArchiveNode) readArchive(ctx context.Context) ([]byte, error) {
+	if node.local() {
+		return os.ReadFile(node.archive)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", node.source.String(), nil)
+	if err != nil {
+		return nil, errors.TaskfileFetchFailedError{URI: node.Location()}
+	}
+	resp, err := node.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, errors.TaskfileFetchFailedError{URI: node.Location()}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.TaskfileFetchFailedError{
+			URI:            node.Location(),
+			HTTPStatusCode: resp.StatusCode,
+		}
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	node.resolved = resp.Request.URL
+	return b, nil
+}
+
+// resolveFile returns the path of the Taskfile in the extracted archive. When
+// no path is given and the root of the archive has no Taskfile, but a single
+// directory, as most release archives do, the Taskfile is searched for in this
+// directory.
+func (node *ArchiveNode) resolveFile() (string, error) {
+	if node.file != "" {
+		return node.file, nil
+	}
+	dir := node.extractDir()
+	if !node.extracted() {
+		return "", fmt.Errorf("task: %q was not extracted", node.Location())
+	}
+
+	searchPath := filepath.Join(dir, filepath.FromSlash(node.path))
+	if rel, err := filepath.Rel(dir, searchPath); err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("task: %q is outside of the archive %q", node.path, node.archive)
+	}
+	file, err := fsext.SearchPath(searchPath, DefaultTaskfiles)
+	if err != nil && node.path == "" {
+		if entries, _ := os.ReadDir(dir); len(entries) == 1 && entries[0].IsDir() {
+			file, err = fsext.SearchPath(filepath.Join(dir, entries[0].Name()), DefaultTaskfiles)
+		}
+	}
+	if err != nil {
+		return "", errors.TaskfileNotFoundError{URI: node.Location()}
+	}
+	node.file = file
+	return file, nil
+}
+
+// File returns the path of the Taskfile in the extracted archive, or an empty
+// string if the archive is not extracted.
+func (node *ArchiveNode) File() string {
+	file, _ := node.resolveFile()
+	return file
+}
+
+// ResolvedURL returns the URL the archive was downloaded from, after following
+// redirects, or its path if it is local.
+func (node *ArchiveNode) ResolvedURL() string {
+	switch {
+	case node.resolved != nil:
+		return node.resolved.Redacted()
+	case node.source != nil:
+		return node.source.Redacted()
+	default:
+		return node.archive
+	}
+}
+
+// ResolveEntrypoint resolves relative entrypoints inside the archive, relative
+// to the Taskfile.
+func (node *ArchiveNode) ResolveEntrypoint(entrypoint string) (string, error) {
+	// If the file is remote, we don't need to resolve the path
+	if isRemoteEntrypoint(entrypoint) {
+		return entrypoint, nil
+	}
+	file, err := node.resolveFile()
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(node.extractDir(), filepath.Dir(file))
+	if err != nil {
+		return "", err
+	}
+	return joinArchiveEntrypoint(node.archive, path.Join(filepath.ToSlash(rel), entrypoint)), nil
+}
+
+// ResolveDir resolves relative directories inside the extracted archive,
+// relative to the Taskfile.
+func (node *ArchiveNode) ResolveDir(dir string) (string, error) {
+	path, err := execext.ExpandLiteral(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if filepathext.IsAbs(path) {
+		return path, nil
+	}
+
+	file, err := node.resolveFile()
+	if err != nil {
+		return "", err
+	}
+	return filepathext.SmartJoin(filepath.Dir(file), path), nil
+}
+
+func (node *ArchiveNode) CacheKey() string {
+	checksum := strings.TrimRight(checksum([]byte(node.Location())), "=")
+	host := "local"
+	if node.url != nil {
+		host = node.url.Host
+	}
+	prefix := path.Base(filepath.ToSlash(node.archive))
+	if node.path != "" {
+		prefix = fmt.Sprintf("%s.%s", prefix, path.Base(node.path))
+	}
+	return fmt.Sprintf("archive.%s.%s.%s", host, prefix, checksum)
+}
+
+// archiveFormat returns the extension of the archive the given entrypoint
+// points to, or an empty string if it isn't an archive. Only local archives and
+// the ones downloaded via HTTP are supported.
+func archiveFormat(entrypoint string) string {
+	scheme, archive, found := strings.Cut(entrypoint, "://")
+	if !found {
+		archive = entrypoint
+	} else if scheme != "http" && scheme != "https" {
+		return ""
+	} else {
+		archive, _, _ = strings.Cut(archive, "?")
+	}
+	archive, _, _ = strings.Cut(archive, "//")
+	archive = strings.ToLower(archive)
+	for ext := range archiveDecompressors {
+		if strings.HasSuffix(archive, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// splitArchiveEntrypoint splits the entrypoint of a local archive into the path
+// of the archive and the path of the Taskfile inside it. Other entrypoints are
+// returned as is.
+func splitArchiveEntrypoint(entrypoint string) (string, string) {
+	if archiveFormat(entrypoint) == "" {
+		return entrypoint, ""
+	}
+	archive, path, _ := strings.Cut(entrypoint, "//")
+	return archive, path
+}
+
+// joinArchiveEntrypoint joins the location of an archive and the path of the
+// Taskfile inside it, if any. The path goes before the query of URLs, which is
+// where it is split from by [NewArchiveNode].
+func joinArchiveEntrypoint(archive, path string) string {
+	if path == "" {
+		return archive
+	}
+	if strings.Contains(archive, "://") {
+		if base, query, found := strings.Cut(archive, "?"); found {
+			return base + "//" + path + "?" + query
+		}
+	}
+	return archive + "//" + path
+}
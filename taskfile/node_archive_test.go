@@ -0,0 +1,178 @@
+package taskfile
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+}
+
+func TestArchiveFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"https://example.com/lib-1.0.tar.gz":                    ".tar.gz",
+		"https://example.com/lib-1.0.tgz//sub/Taskfile.yml":     ".tgz",
+		"https://example.com/lib.zip?token=abc":                 ".zip",
+		"./lib.ZIP//Taskfile.yml":                               ".zip",
+		"/tmp/lib.tar.gz":                                       ".tar.gz",
+		"https://example.com/Taskfile.yml":                      "",
+		"https://github.com/foo/bar.git//lib.zip":               "",
+		"git::https://example.com/lib.zip":                      "",
+		"ssh://git@example.com/lib.tar.gz":                      "",
+		"https://example.com/lib.tar.gz.d/Taskfile.yml":         "",
+		"https://example.com/releases/download/v1/Taskfile.yml": "",
+	}
+	for entrypoint, format := range tests {
+		assert.Equal(t, format, archiveFormat(entrypoint), entrypoint)
+	}
+}
+
+func TestArchiveNode_local(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "lib-1.0.tar.gz")
+	writeTarGz(t, archive, map[string]string{
+		"lib-1.0/Taskfile.yml":     "version: '3'\n",
+		"lib-1.0/Taskfile.yml.sig": "signature",
+		"lib-1.0/sub/Taskfile.yml": "version: '3'\n\ntasks:\n  sub: echo sub\n",
+		"lib-1.0/scripts/hello.sh": "echo hello\n",
+	})
+
+	// The Taskfile is searched for in the single directory of the archive
+	node, err := NewArchiveNode(archive, "", false)
+	require.NoError(t, err)
+	node.setCacheDir(filepath.Join(dir, "cache"))
+	assert.Equal(t, archive, node.Location())
+	b, err := node.ReadContext(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "version: '3'\n", string(b))
+	extracted := filepath.Join(node.extractDir(), "lib-1.0")
+	assert.Equal(t, filepath.Join(extracted, "Taskfile.yml"), node.File())
+	assert.FileExists(t, filepath.Join(extracted, "scripts", "hello.sh"))
+	sig, err := node.ReadSignature(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "signature", string(sig))
+
+	entrypoint, err := node.ResolveEntrypoint("./sub/other.yml")
+	require.NoError(t, err)
+	assert.Equal(t, archive+"//lib-1.0/sub/other.yml", entrypoint)
+	resolvedDir, err := node.ResolveDir("scripts")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(extracted, "scripts"), resolvedDir)
+
+	// A path inside the archive
+	node, err = NewArchiveNode(archive+"//lib-1.0/sub", "", false)
+	require.NoError(t, err)
+	node.setCacheDir(filepath.Join(dir, "cache"))
+	b, err = node.ReadContext(t.Context())
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "echo sub")
+	assert.Equal(t, filepath.Join(extracted, "sub", "Taskfile.yml"), node.File())
+
+	// Paths outside of the archive are rejected
+	node, err = NewArchiveNode(archive+"//../Taskfile.yml", "", false)
+	require.NoError(t, err)
+	node.setCacheDir(filepath.Join(dir, "cache"))
+	_, err = node.ReadContext(t.Context())
+	assert.ErrorContains(t, err, "outside of the archive")
+
+	_, err = NewArchiveNode(filepath.Join(dir, "missing.zip"), "", false)
+	assert.Error(t, err)
+}
+
+func TestArchiveNode_query(t *testing.T) {
+	t.Parallel()
+
+	// The path inside the archive goes before the query, so that the location
+	// can be parsed again
+	const entrypoint = "https://example.com/lib.tar.gz//sub/Taskfile.yml?token=x"
+	node, err := NewArchiveNode(entrypoint, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "sub/Taskfile.yml", node.path)
+	assert.Equal(t, entrypoint, node.Location())
+
+	node, err = NewArchiveNode(node.Location(), "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "sub/Taskfile.yml", node.path)
+	assert.Equal(t, "https://example.com/lib.tar.gz?token=x", node.source.String())
+	assert.Equal(t, "https://example.com/lib.tar.gz//common.yml?token=x", joinArchiveEntrypoint(node.archive, "common.yml"))
+}
+
+func TestArchiveNode_remote(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("Taskfile.yml")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("version: '3'\n"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/mirror/lib.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	_, err = NewArchiveNode(srv.URL+"/lib.zip", "", false)
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	node, err := NewArchiveNode("https://example.com/lib.zip//Taskfile.yml", "", true,
+		WithMirrors(map[string]string{"https://example.com/": srv.URL + "/mirror/"}))
+	require.NoError(t, err)
+	node.setCacheDir(dir)
+	assert.Equal(t, "https://example.com/lib.zip//Taskfile.yml", node.Location())
+	assert.True(t, strings.HasPrefix(node.CacheKey(), "archive.example.com.lib.zip.Taskfile.yml."), node.CacheKey())
+	b, err := node.ReadContext(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "version: '3'\n", string(b))
+	assert.Equal(t, srv.URL+"/mirror/lib.zip", node.ResolvedURL())
+
+	// The archive is only extracted once until the cache is cleaned
+	_, err = node.ReadContext(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	entrypoint, err := node.ResolveEntrypoint("common.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/lib.zip//common.yml", entrypoint)
+
+	entries, err := listArchiveCache(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "https://example.com/lib.zip", entries[0].URL)
+	assert.Equal(t, checksum(buf.Bytes()), entries[0].Checksum)
+}
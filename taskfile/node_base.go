@@ -1,5 +1,7 @@
 package taskfile
 
+import "strings"
+
 type (
 	NodeOption func(*baseNode)
 	// baseNode is a generic node that implements the Parent() methods of the
@@ -13,6 +15,10 @@ type (
 		caCert   string
 		cert     string
 		certKey  string
+		mirrors  map[string]string
+		// credentials are never part of the location nor the cache key of
+		// nodes
+		credentials map[string]Credentials
 	}
 )
 
@@ -75,3 +81,26 @@ func WithCertKey(certKey string) NodeOption {
 		node.certKey = certKey
 	}
 }
+
+// WithMirrors sets the URL prefixes of remote Taskfiles to replace with the
+// prefix of a mirror when downloading them.
+func WithMirrors(mirrors map[string]string) NodeOption {
+	return func(node *baseNode) {
+		node.mirrors = mirrors
+	}
+}
+
+// mirror returns the URL the given remote entrypoint is downloaded from. If
+// several mirror prefixes match, the longest one is used.
+func (node *baseNode) mirror(uri string) string {
+	var prefix string
+	for p := range node.mirrors {
+		if strings.HasPrefix(uri, p) && len(p) > len(prefix) {
+			prefix = p
+		}
+	}
+	if prefix == "" {
+		return uri
+	}
+	return node.mirrors[prefix] + strings.TrimPrefix(uri, prefix)
+}
@@ -66,6 +66,45 @@ func (node *CacheNode) WriteChecksum(checksum string) error {
 	return os.WriteFile(node.checksumPath(), []byte(checksum), 0o644)
 }
 
+// WriteURL records the location of the source of the cached Taskfile, so that
+// the cache can be listed.
+func (node *CacheNode) WriteURL(url string) error {
+	if err := node.CreateCacheDir(); err != nil {
+		return err
+	}
+	return os.WriteFile(node.urlPath(), []byte(url), 0o644)
+}
+
+// ReadCommit returns the commit the cached Taskfile was read from, if it comes
+// from a git repository.
+func (node *CacheNode) ReadCommit() string {
+	b, _ := os.ReadFile(node.commitPath())
+	return string(b)
+}
+
+func (node *CacheNode) WriteCommit(commit string) error {
+	if err := node.CreateCacheDir(); err != nil {
+		return err
+	}
+	return os.WriteFile(node.commitPath(), []byte(commit), 0o644)
+}
+
+// ReadSignature returns the signature of the cached Taskfile, or nil if it
+// was cached without one.
+func (node *CacheNode) ReadSignature() []byte {
+	b, err := os.ReadFile(node.signaturePath())
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// WriteSignature stores the signature of the cached Taskfile. A nil signature
+// removes the stored one.
+func (node *CacheNode) WriteSignature(sig []byte) error {
+	return writeSignature(node.signaturePath(), sig)
+}
+
 func (node *CacheNode) CreateCacheDir() error {
 	if err := os.MkdirAll(node.dir, 0o755); err != nil {
 		return err
@@ -102,10 +141,35 @@ func (node *CacheNode) timestampPath() string {
 	return node.filePath("timestamp")
 }
 
+func (node *CacheNode) commitPath() string {
+	return node.filePath("commit")
+}
+
+func (node *CacheNode) signaturePath() string {
+	return node.filePath("sig")
+}
+
+func (node *CacheNode) urlPath() string {
+	return node.filePath("url")
+}
+
 func (node *CacheNode) filePath(suffix string) string {
 	return filepath.Join(node.dir, fmt.Sprintf("%s.%s", node.source.CacheKey(), suffix))
 }
 
+func writeSignature(path string, sig []byte) error {
+	if sig == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, sig, 0o644)
+}
+
 func checksum(b []byte) string {
 	h := sha256.New()
 	h.Write(b)
@@ -64,19 +64,22 @@ func (node *FileNode) ResolveEntrypoint(entrypoint string) (string, error) {
 		return entrypoint, nil
 	}
 
+	// Only the path of local archives is resolved, not the path of the
+	// Taskfile inside them
+	entrypoint, pathInArchive := splitArchiveEntrypoint(entrypoint)
 	path, err := execext.ExpandLiteral(entrypoint)
 	if err != nil {
 		return "", err
 	}
 
-	if filepathext.IsAbs(path) {
-		return path, nil
-	}
-
 	// NOTE: Uses the directory of the entrypoint (Taskfile), not the current working directory
 	// This means that files are included relative to one another
-	entrypointDir := filepath.Dir(node.entrypoint)
-	return filepathext.SmartJoin(entrypointDir, path), nil
+	if !filepathext.IsAbs(path) {
+		entrypointDir := filepath.Dir(node.entrypoint)
+		path = filepathext.SmartJoin(entrypointDir, path)
+	}
+
+	return joinArchiveEntrypoint(path, pathInArchive), nil
 }
 
 func (node *FileNode) ResolveDir(dir string) (string, error) {
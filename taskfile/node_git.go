@@ -1,14 +1,18 @@
 package taskfile
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"net/url"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	giturls "github.com/chainguard-dev/git-urls"
 	"github.com/hashicorp/go-getter"
@@ -22,15 +26,42 @@ import (
 // An GitNode is a node that reads a Taskfile from a remote location via Git.
 type GitNode struct {
 	*baseNode
-	url    *url.URL
+	url *url.URL
+	// source is the URL of the repository the Taskfile is cloned from, which
+	// differs from url when it is mirrored
+	source *url.URL
 	rawUrl string
 	ref    string
 	path   string
+	commit string
+	// locked is the commit pinned by the lock file, if any
+	locked string
+	// file is the path of the Taskfile in the cloned repository, or in its
+	// vendored copy, once read
+	file string
+	// vendorDir is the directory of the vendored copy of the repository, if
+	// the Taskfile is vendored
+	vendorDir string
+	// cacheDir is the directory the repository is cloned into, which is a
+	// temporary directory if empty
+	cacheDir string
+	// expiry is how long the clone is used before the ref is fetched again
+	expiry time.Duration
+	// refresh forces the ref to be fetched again
+	refresh bool
 }
 
+const (
+	// gitCacheDirName is the directory of the git clones in the remote cache
+	gitCacheDirName = "git"
+	// gitTimestampFile records when the ref was last fetched in a clone
+	gitTimestampFile = "task.timestamp"
+)
+
 type gitRepoCache struct {
-	mu    sync.Mutex             // Protects the locks map
-	locks map[string]*sync.Mutex // One mutex per repo cache key
+	mu      sync.Mutex             // Protects the locks and fetched maps
+	locks   map[string]*sync.Mutex // One mutex per repo cache key
+	fetched map[string]bool        // Clones fetched since the cache was cleaned
 }
 
 func (c *gitRepoCache) getLockForRepo(cacheKey string) *sync.Mutex {
@@ -44,18 +75,38 @@ func (c *gitRepoCache) getLockForRepo(cacheKey string) *sync.Mutex {
 	return c.locks[cacheKey]
 }
 
+// markFetched reports whether the given clone was already cloned or fetched
+// since the cache was cleaned, and marks it as such.
+func (c *gitRepoCache) markFetched(dir string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fetched := c.fetched[dir]
+	c.fetched[dir] = true
+	return fetched
+}
+
 var globalGitRepoCache = &gitRepoCache{
-	locks: make(map[string]*sync.Mutex),
+	locks:   make(map[string]*sync.Mutex),
+	fetched: make(map[string]bool),
 }
 
+// CleanGitCache resets the state of the git clones, so that refs are fetched
+// again by the next read, and removes the temporary clones.
 func CleanGitCache() error {
-	// Clear the in-memory locks map to prevent memory leak
+	// Clear the in-memory maps to prevent memory leak
 	globalGitRepoCache.mu.Lock()
 	globalGitRepoCache.locks = make(map[string]*sync.Mutex)
+	globalGitRepoCache.fetched = make(map[string]bool)
 	globalGitRepoCache.mu.Unlock()
 
-	cacheDir := filepath.Join(os.TempDir(), "task-git-repos")
-	return os.RemoveAll(cacheDir)
+	return os.RemoveAll(tempGitCacheDir())
+}
+
+// tempGitCacheDir returns the directory git repositories are cloned into when
+// no cache directory is set.
+func tempGitCacheDir() string {
+	return filepath.Join(os.TempDir(), "task-git-repos")
 }
 
 func NewGitNode(
@@ -78,12 +129,22 @@ func NewGitNode(
 	u.RawQuery = ""
 	u.Path = basePath
 
-	if u.Scheme == "http" && !insecure {
-		return nil, &errors.TaskfileNotSecureError{URI: u.Redacted()}
+	source := u
+	if mirror := base.mirror(entrypoint); mirror != entrypoint {
+		if source, err = giturls.Parse(mirror); err != nil {
+			return nil, err
+		}
+		source.Path, _ = splitURLOnDoubleSlash(source)
+		source.RawQuery = ""
+	}
+
+	if (u.Scheme == "http" || source.Scheme == "http") && !insecure {
+		return nil, &errors.TaskfileNotSecureError{URI: source.Redacted()}
 	}
 	return &GitNode{
 		baseNode: base,
 		url:      u,
+		source:   source,
 		rawUrl:   rawUrl,
 		ref:      ref,
 		path:     path,
@@ -104,10 +165,14 @@ func (node *GitNode) Read() ([]byte, error) {
 
 func (node *GitNode) buildURL() string {
 	// Get the base URL
-	baseURL := node.url.String()
+	baseURL := node.source.String()
 
 	// Always use git:: prefix for git URLs (following Terraform's pattern)
 	// This forces go-getter to use git protocol
+	if node.locked != "" {
+		// A shallow clone requires the ref to be a branch or a tag
+		return fmt.Sprintf("git::%s?ref=%s", baseURL, node.locked)
+	}
 	if node.ref != "" {
 		return fmt.Sprintf("git::%s?ref=%s&depth=1", baseURL, node.ref)
 	}
@@ -115,47 +180,175 @@ func (node *GitNode) buildURL() string {
 	return fmt.Sprintf("git::%s?depth=1", baseURL)
 }
 
+// gitEnv returns the environment of the git commands run for the repository,
+// or nil to use the one of Task. The credentials configured for the host of
+// the repository are passed to git as configuration in the environment, so
+// they never appear in its arguments nor in the clone. They are only used over
+// HTTP, as SSH uses keys, and never override the ones set in the URL.
+func (node *GitNode) gitEnv() []string {
+	if node.source.User != nil || (node.source.Scheme != "https" && node.source.Scheme != "http") {
+		return nil
+	}
+	credentials, ok := credentialsFor(node.credentials, node.source)
+	if !ok {
+		return nil
+	}
+	config := credentials.gitConfig(fmt.Sprintf("%s://%s/", node.source.Scheme, node.source.Host))
+	if config == nil {
+		return nil
+	}
+	// Entries already set in the environment are kept
+	env := os.Environ()
+	count, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	for i := 0; i < len(config); i += 2 {
+		env = append(env,
+			fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", count, config[i]),
+			fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", count, config[i+1]),
+		)
+		count++
+	}
+	return append(env, fmt.Sprintf("GIT_CONFIG_COUNT=%d", count))
+}
+
+// git runs git with the given arguments in the given directory, with the
+// credentials of the repository, if any.
+func (node *GitNode) git(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = node.gitEnv()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// clone clones the repository into the given directory like go-getter does
+// with the URL returned by buildURL. go-getter runs git with the environment
+// of Task, so it is only used for repositories without credentials.
+func (node *GitNode) clone(ctx context.Context, repoDir string) error {
+	clone := []string{"clone", "--quiet"}
+	submodules := []string{"submodule", "update", "--quiet", "--init", "--recursive"}
+	if node.locked == "" {
+		clone = append(clone, "--depth", "1")
+		submodules = append(submodules, "--depth", "1")
+		if node.ref != "" {
+			clone = append(clone, "--branch", node.ref)
+		}
+	}
+	if err := node.git(ctx, "", append(clone, "--", node.source.String(), repoDir)...); err != nil {
+		return err
+	}
+	if node.locked != "" {
+		if err := node.git(ctx, repoDir, "checkout", "--quiet", node.locked); err != nil {
+			return err
+		}
+	}
+	return node.git(ctx, repoDir, submodules...)
+}
+
+// setCache sets the directory the repository is cloned into, and how long the
+// clone is used before its ref is fetched again, unless refresh is set.
+func (node *GitNode) setCache(dir string, expiry time.Duration, refresh bool) {
+	node.cacheDir = dir
+	node.expiry = expiry
+	node.refresh = refresh
+}
+
 // getOrCloneRepo returns the path to a cached git repository.
-// If the repository is not cached, it clones it first.
+// If the repository is not cached, it clones it first. If it is, its ref is
+// fetched again once the cache expired, at most once until the cache is
+// cleaned. Locked commits never change, so they are never fetched again.
 // This function is thread-safe: multiple goroutines cloning the same repo+ref
 // will synchronize, and only one clone operation will occur.
 //
-// The cache directory is /tmp/task-git-repos/{cache_key}/
+// The cache directory is {cache_dir}/git/{cache_key}/
 func (node *GitNode) getOrCloneRepo(ctx context.Context) (string, error) {
 	cacheKey := node.repoCacheKey()
+	baseDir := tempGitCacheDir()
+	if node.cacheDir != "" {
+		baseDir = node.cacheDir
+	}
+	cacheDir := filepath.Join(baseDir, cacheKey)
 
-	repoMutex := globalGitRepoCache.getLockForRepo(cacheKey)
+	repoMutex := globalGitRepoCache.getLockForRepo(cacheDir)
 	repoMutex.Lock()
 	defer repoMutex.Unlock()
 
-	cacheDir := filepath.Join(os.TempDir(), "task-git-repos", cacheKey)
-
 	// Check cache FIRST - if already cloned, no network needed, timeout irrelevant
 	gitDir := filepath.Join(cacheDir, ".git")
 	if _, err := os.Stat(gitDir); err == nil {
+		if node.locked != "" || globalGitRepoCache.markFetched(cacheDir) {
+			return cacheDir, nil
+		}
+		if !node.refresh && time.Since(readGitTimestamp(cacheDir)) < node.expiry {
+			return cacheDir, nil
+		}
+		if err := node.fetch(ctx, cacheDir); err != nil {
+			return "", err
+		}
 		return cacheDir, nil
 	}
+	globalGitRepoCache.markFetched(cacheDir)
 
 	// Only check context if we need to clone (requires network)
 	if err := ctx.Err(); err != nil {
 		return "", fmt.Errorf("context cancelled while waiting for repository lock: %w", err)
 	}
 
-	getterURL := node.buildURL()
-
-	client := &getter.Client{
-		Ctx:  ctx,
-		Src:  getterURL,
-		Dst:  cacheDir,
-		Mode: getter.ClientModeDir,
+	var err error
+	if node.gitEnv() != nil {
+		err = node.clone(ctx, cacheDir)
+	} else {
+		client := &getter.Client{
+			Ctx:  ctx,
+			Src:  node.buildURL(),
+			Dst:  cacheDir,
+			Mode: getter.ClientModeDir,
+		}
+		err = client.Get()
 	}
-
-	if err := client.Get(); err != nil {
+	if err != nil {
 		_ = os.RemoveAll(cacheDir)
 		return "", fmt.Errorf("failed to clone repository: %w", err)
 	}
 
-	return cacheDir, nil
+	return cacheDir, writeGitTimestamp(cacheDir)
+}
+
+// fetch fetches the ref of the node again in the given clone, and checks it
+// out.
+func (node *GitNode) fetch(ctx context.Context, repoDir string) error {
+	ref := node.ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	for _, args := range [][]string{
+		{"fetch", "--quiet", "--depth", "1", "--", node.source.String(), ref},
+		{"reset", "--quiet", "--hard", "FETCH_HEAD"},
+	} {
+		if err := node.git(ctx, repoDir, args...); err != nil {
+			return fmt.Errorf("failed to update repository %q: %w", node.source.Redacted(), err)
+		}
+	}
+	return writeGitTimestamp(repoDir)
+}
+
+// readGitTimestamp returns when the ref of the given clone was last fetched.
+func readGitTimestamp(repoDir string) time.Time {
+	b, err := os.ReadFile(filepath.Join(repoDir, ".git", gitTimestampFile))
+	if err != nil {
+		return time.Time{}
+	}
+	timestamp, err := time.Parse(time.RFC3339, string(b))
+	if err != nil {
+		return time.Time{}
+	}
+	return timestamp
+}
+
+func writeGitTimestamp(repoDir string) error {
+	path := filepath.Join(repoDir, ".git", gitTimestampFile)
+	return os.WriteFile(path, []byte(time.Now().UTC().Format(time.RFC3339)), 0o644)
 }
 
 func (node *GitNode) ReadContext(ctx context.Context) ([]byte, error) {
@@ -164,15 +357,11 @@ func (node *GitNode) ReadContext(ctx context.Context) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	// Build path to Taskfile in the cached repo
-	// If node.path is empty, search in repo root; otherwise search in the specified path
-	// fsext.SearchPath handles both files and directories (searching for DefaultTaskfiles)
-	searchPath := repoDir
-	if node.path != "" {
-		searchPath = filepath.Join(repoDir, node.path)
+	if node.commit, err = headCommit(ctx, repoDir); err != nil {
+		return nil, err
 	}
-	filePath, err := fsext.SearchPath(searchPath, DefaultTaskfiles)
+
+	filePath, err := node.searchTaskfile(repoDir)
 	if err != nil {
 		return nil, err
 	}
@@ -183,9 +372,129 @@ func (node *GitNode) ReadContext(ctx context.Context) ([]byte, error) {
 		return nil, err
 	}
 
+	node.file = filePath
 	return b, nil
 }
 
+// searchTaskfile returns the path of the Taskfile in the given copy of the
+// repository. If node.path is empty, it is searched for in the root of the
+// repository, otherwise in the specified path, which can be a file or a
+// directory.
+func (node *GitNode) searchTaskfile(repoDir string) (string, error) {
+	searchPath := repoDir
+	if node.path != "" {
+		searchPath = filepath.Join(repoDir, node.path)
+	}
+	return fsext.SearchPath(searchPath, DefaultTaskfiles)
+}
+
+// File returns the path of the Taskfile in the cached clone of the repository
+// or in its vendored copy, or an empty string if it was not read from one of
+// them.
+func (node *GitNode) File() string {
+	if node.cacheDir == "" && node.vendorDir == "" {
+		return ""
+	}
+	return node.file
+}
+
+// useVendored makes the node use the files of the vendored copy of the
+// repository in the given directory.
+func (node *GitNode) useVendored(dir string) error {
+	file, err := node.searchTaskfile(dir)
+	if err != nil {
+		return err
+	}
+	node.vendorDir = dir
+	node.file = file
+	return nil
+}
+
+// restoreFile sets the path of the Taskfile in the cached clone of the
+// repository, when the Taskfile is read from the cache instead.
+func (node *GitNode) restoreFile() {
+	if node.file != "" || node.cacheDir == "" {
+		return
+	}
+	repoDir := filepath.Join(node.cacheDir, node.repoCacheKey())
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); err != nil {
+		return
+	}
+	if file, err := node.searchTaskfile(repoDir); err == nil {
+		node.file = file
+	}
+}
+
+// writeFiles writes the files of the repository at the commit the Taskfile
+// was read from into the given directory, without the history.
+func (node *GitNode) writeFiles(ctx context.Context, dir string) error {
+	if node.file == "" || node.vendorDir != "" {
+		return fmt.Errorf("task: %q was not read from the repository", node.Location())
+	}
+	repoDir := filepath.Join(node.cacheDir, node.repoCacheKey())
+	if node.cacheDir == "" {
+		repoDir = filepath.Join(tempGitCacheDir(), node.repoCacheKey())
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	// Check the files out with a temporary index, to leave the clone as is
+	indexDir, err := os.MkdirTemp("", "task-vendor")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(indexDir)
+	cmd := exec.CommandContext(ctx, "git", "--work-tree", dir, "checkout", node.Commit(), "--", ".")
+	cmd.Dir = repoDir
+	cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+filepath.Join(indexDir, "index"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to vendor repository %q: %w: %s", node.source.Redacted(), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ReadSignature returns the signature committed next to the Taskfile in the
+// repository, if any.
+func (node *GitNode) ReadSignature(ctx context.Context) ([]byte, error) {
+	if node.file == "" {
+		return nil, fmt.Errorf("task: %q was not read from the repository", node.Location())
+	}
+	b, err := os.ReadFile(node.file + SignatureExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// ResolvedURL returns the URL of the repository the Taskfile is cloned from,
+// without the path of the Taskfile and the ref.
+func (node *GitNode) ResolvedURL() string {
+	return node.source.Redacted()
+}
+
+// Commit returns the SHA of the commit the Taskfile was read from. It is empty
+// until the node is read, unless it is locked.
+func (node *GitNode) Commit() string {
+	return cmp.Or(node.commit, node.locked)
+}
+
+// lock makes the node read the Taskfile from the given commit instead of its
+// ref, so that branches moving don't change the Taskfile.
+func (node *GitNode) lock(commit string) {
+	node.locked = commit
+}
+
+// headCommit returns the SHA of the commit checked out in the given repository.
+func headCommit(ctx context.Context, repoDir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the commit of the repository: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 func (node *GitNode) ResolveEntrypoint(entrypoint string) (string, error) {
 	// If the file is remote, we don't need to resolve the path
 	if isRemoteEntrypoint(entrypoint) {
@@ -210,6 +519,12 @@ func (node *GitNode) ResolveDir(dir string) (string, error) {
 		return path, nil
 	}
 
+	// Like archives, relative directories are resolved inside the repository,
+	// relative to the Taskfile
+	if file := node.File(); file != "" {
+		return filepathext.SmartJoin(filepath.Dir(file), path), nil
+	}
+
 	// NOTE: Uses the directory of the entrypoint (Taskfile), not the current working directory
 	// This means that files are included relative to one another
 	entrypointDir := filepath.Dir(node.Dir())
@@ -233,14 +548,17 @@ func (node *GitNode) CacheKey() string {
 //
 // Returns a path like: github.com/user/repo.git/main
 func (node *GitNode) repoCacheKey() string {
-	repoPath := strings.Trim(node.url.Path, "/")
+	repoPath := strings.Trim(node.source.Path, "/")
 
 	ref := node.ref
+	if node.locked != "" {
+		ref = node.locked
+	}
 	if ref == "" {
 		ref = "_default_" // Placeholder for the remote's default branch
 	}
 
-	return filepath.Join(node.url.Host, repoPath, ref)
+	return filepath.Join(node.source.Host, repoPath, ref)
 }
 
 func splitURLOnDoubleSlash(u *url.URL) (string, string) {
@@ -20,8 +20,10 @@ import (
 // An HTTPNode is a node that reads a Taskfile from a remote location via HTTP.
 type HTTPNode struct {
 	*baseNode
-	url    *url.URL     // stores url pointing actual remote file. (e.g. with Taskfile.yml)
-	client *http.Client // HTTP client with optional TLS configuration
+	url      *url.URL     // stores url pointing actual remote file. (e.g. with Taskfile.yml)
+	source   *url.URL     // url the Taskfile is downloaded from, which differs from url when it is mirrored
+	client   *http.Client // HTTP client with optional TLS configuration
+	resolved *url.URL     // url the Taskfile was actually downloaded from, once read
 }
 
 // buildHTTPClient creates an HTTP client with optional TLS configuration.
@@ -81,18 +83,24 @@ func NewHTTPNode(
 	if err != nil {
 		return nil, err
 	}
-	if url.Scheme == "http" && !insecure {
-		return nil, &errors.TaskfileNotSecureError{URI: url.Redacted()}
+	source, err := url.Parse(base.mirror(entrypoint))
+	if err != nil {
+		return nil, err
+	}
+	if (url.Scheme == "http" || source.Scheme == "http") && !insecure {
+		return nil, &errors.TaskfileNotSecureError{URI: source.Redacted()}
 	}
 
 	client, err := buildHTTPClient(insecure, base.caCert, base.cert, base.certKey)
 	if err != nil {
 		return nil, err
 	}
+	client = withCredentials(client, base.credentials)
 
 	return &HTTPNode{
 		baseNode: base,
 		url:      url,
+		source:   source,
 		client:   client,
 	}, nil
 }
@@ -106,7 +114,7 @@ func (node *HTTPNode) Read() ([]byte, error) {
 }
 
 func (node *HTTPNode) ReadContext(ctx context.Context) ([]byte, error) {
-	url, err := RemoteExists(ctx, *node.url, node.client)
+	url, err := RemoteExists(ctx, *node.source, node.client)
 	if err != nil {
 		return nil, err
 	}
@@ -136,9 +144,52 @@ func (node *HTTPNode) ReadContext(ctx context.Context) ([]byte, error) {
 		return nil, err
 	}
 
+	node.resolved = resp.Request.URL
 	return b, nil
 }
 
+func (node *HTTPNode) ReadSignature(ctx context.Context) ([]byte, error) {
+	u := node.source
+	if node.resolved != nil {
+		u = node.resolved
+	}
+	sigURL := *u
+	sigURL.Path += SignatureExt
+	sigURL.RawPath = ""
+	req, err := http.NewRequestWithContext(ctx, "GET", sigURL.String(), nil)
+	if err != nil {
+		return nil, errors.TaskfileFetchFailedError{URI: sigURL.Redacted()}
+	}
+	resp, err := node.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, errors.TaskfileFetchFailedError{URI: sigURL.Redacted()}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.TaskfileFetchFailedError{
+			URI:            sigURL.Redacted(),
+			HTTPStatusCode: resp.StatusCode,
+		}
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// ResolvedURL returns the URL the Taskfile was downloaded from, after
+// following redirects and appending the default Taskfile names. It is empty
+// until the node is read from the network.
+func (node *HTTPNode) ResolvedURL() string {
+	if node.resolved == nil {
+		return ""
+	}
+	return node.resolved.Redacted()
+}
+
 func (node *HTTPNode) ResolveEntrypoint(entrypoint string) (string, error) {
 	ref, err := url.Parse(entrypoint)
 	if err != nil {
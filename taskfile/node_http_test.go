@@ -9,8 +9,10 @@ import (
 	"encoding/pem"
 	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -59,6 +61,99 @@ func TestHTTPNode_CacheKey(t *testing.T) {
 	}
 }
 
+func TestHTTPNode_mirror(t *testing.T) {
+	t.Parallel()
+
+	mirror := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer mirror.Close()
+
+	entrypoint := "https://github.com/foo/Taskfile.yml"
+	node, err := NewHTTPNode(entrypoint, "", true, WithMirrors(map[string]string{"https://github.com/": mirror.URL + "/github/"}))
+	require.NoError(t, err)
+	unmirrored, err := NewHTTPNode(entrypoint, "", false)
+	require.NoError(t, err)
+
+	b, err := node.ReadContext(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "/github/foo/Taskfile.yml", string(b))
+	assert.Equal(t, mirror.URL+"/github/foo/Taskfile.yml", node.ResolvedURL())
+	assert.Equal(t, unmirrored.Location(), node.Location())
+	assert.Equal(t, unmirrored.CacheKey(), node.CacheKey())
+
+	resolved, err := node.ResolveEntrypoint("other.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/foo/other.yml", resolved)
+}
+
+func TestHTTPNode_credentials(t *testing.T) {
+	t.Parallel()
+
+	// Requests to the other server must not receive the credentials
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization") + r.Header.Get("X-Api-Key")))
+	}))
+	defer other.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/redirect") {
+			http.Redirect(w, r, other.URL, http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Authorization") + " " + r.Header.Get("X-Api-Key")))
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	credentials := map[string]Credentials{
+		host: {Token: "secret", Headers: map[string]string{"X-Api-Key": "key"}},
+	}
+	node, err := NewHTTPNode(srv.URL+"/Taskfile.yml", "", true, WithCredentials(credentials))
+	require.NoError(t, err)
+	unauthenticated, err := NewHTTPNode(srv.URL+"/Taskfile.yml", "", true)
+	require.NoError(t, err)
+
+	b, err := node.ReadContext(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret key", string(b))
+	assert.Equal(t, unauthenticated.CacheKey(), node.CacheKey())
+	assert.NotContains(t, node.Location(), "secret")
+
+	node, err = NewHTTPNode(srv.URL+"/redirect", "", true, WithCredentials(credentials))
+	require.NoError(t, err)
+	b, err = node.ReadContext(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, string(b))
+}
+
+func TestHTTPNode_netrc(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		_, _ = w.Write([]byte(username + ":" + password))
+	}))
+	defer srv.Close()
+
+	netrc := filepath.Join(t.TempDir(), ".netrc")
+	require.NoError(t, os.WriteFile(netrc, []byte("machine 127.0.0.1 login user password secret\n"), 0o600))
+	t.Setenv("NETRC", netrc)
+
+	node, err := NewHTTPNode(srv.URL+"/Taskfile.yml", "", true)
+	require.NoError(t, err)
+	b, err := node.ReadContext(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "user:secret", string(b))
+
+	// Configured credentials take precedence
+	host := strings.TrimPrefix(srv.URL, "http://")
+	node, err = NewHTTPNode(srv.URL+"/Taskfile.yml", "", true, WithCredentials(map[string]Credentials{
+		host: {Username: "other", Password: "password"},
+	}))
+	require.NoError(t, err)
+	b, err = node.ReadContext(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "other:password", string(b))
+}
+
 func TestBuildHTTPClient_Default(t *testing.T) {
 	t.Parallel()
 
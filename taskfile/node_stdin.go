@@ -46,16 +46,17 @@ func (node *StdinNode) ResolveEntrypoint(entrypoint string) (string, error) {
 		return entrypoint, nil
 	}
 
+	entrypoint, pathInArchive := splitArchiveEntrypoint(entrypoint)
 	path, err := execext.ExpandLiteral(entrypoint)
 	if err != nil {
 		return "", err
 	}
 
-	if filepathext.IsAbs(path) {
-		return path, nil
+	if !filepathext.IsAbs(path) {
+		path = filepathext.SmartJoin(node.Dir(), path)
 	}
 
-	return filepathext.SmartJoin(node.Dir(), path), nil
+	return joinArchiveEntrypoint(path, pathInArchive), nil
 }
 
 func (node *StdinNode) ResolveDir(dir string) (string, error) {
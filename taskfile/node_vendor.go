@@ -0,0 +1,64 @@
+package taskfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// VendorDir is the directory, relative to the root Taskfile, where remote
+// Taskfiles are vendored by "task --vendor".
+var VendorDir = filepath.Join(".task", "vendor")
+
+// A VendorNode is a node that reads a vendored copy of a remote Taskfile.
+// Unlike the [CacheNode], vendored copies are meant to be committed, and are
+// used instead of the network until they are vendored again.
+type VendorNode struct {
+	*baseNode
+	source RemoteNode
+}
+
+func NewVendorNode(source RemoteNode, dir string) *VendorNode {
+	return &VendorNode{
+		baseNode: &baseNode{
+			dir: dir,
+		},
+		source: source,
+	}
+}
+
+func (node *VendorNode) Read() ([]byte, error) {
+	return os.ReadFile(node.Location())
+}
+
+func (node *VendorNode) Write(data []byte) error {
+	if err := os.MkdirAll(node.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(node.Location(), data, 0o644)
+}
+
+// ReadSignature returns the signature of the vendored Taskfile, or nil if it
+// was vendored without one.
+func (node *VendorNode) ReadSignature() []byte {
+	b, err := os.ReadFile(node.Location() + SignatureExt)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// WriteSignature stores the signature of the vendored Taskfile. A nil
+// signature removes the stored one.
+func (node *VendorNode) WriteSignature(sig []byte) error {
+	return writeSignature(node.Location()+SignatureExt, sig)
+}
+
+// FilesDir returns the directory of the vendored files of the repository of
+// git Taskfiles, which their tasks can use.
+func (node *VendorNode) FilesDir() string {
+	return filepath.Join(node.dir, node.source.CacheKey())
+}
+
+func (node *VendorNode) Location() string {
+	return filepath.Join(node.dir, node.source.CacheKey()+".yaml")
+}
@@ -1,20 +1,27 @@
 package taskfile
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/dominikbraun/graph"
 	"go.yaml.in/yaml/v3"
+	"golang.org/x/crypto/ssh"
 	"golang.org/x/sync/errgroup"
+	"mvdan.cc/sh/v3/interp"
 
 	"github.com/go-task/task/v3/errors"
 	"github.com/go-task/task/v3/internal/env"
+	"github.com/go-task/task/v3/internal/execext"
 	"github.com/go-task/task/v3/internal/filepathext"
+	"github.com/go-task/task/v3/internal/fsext"
 	"github.com/go-task/task/v3/internal/templater"
 	"github.com/go-task/task/v3/taskfile/ast"
 )
@@ -45,14 +52,25 @@ type (
 		download            bool
 		offline             bool
 		trustedHosts        []string
+		trustedKeys         []string
+		publicKeys          []ssh.PublicKey
 		tempDir             string
 		cacheExpiryDuration time.Duration
 		caCert              string
 		cert                string
 		certKey             string
+		mirrors             map[string]string
+		credentials         map[string]Credentials
 		debugFunc           DebugFunc
 		promptFunc          PromptFunc
 		promptMutex         sync.Mutex
+		lockfile            string
+		lockMode            LockMode
+		lock                *Lockfile
+		newLock             *Lockfile
+		lockMutex           sync.Mutex
+		vendorDir           string
+		vendor              bool
 	}
 )
 
@@ -70,6 +88,10 @@ func NewReader(opts ...ReaderOption) *Reader {
 		debugFunc:           nil,
 		promptFunc:          nil,
 		promptMutex:         sync.Mutex{},
+		lockfile:            "",
+		lockMode:            LockModeVerify,
+		vendorDir:           "",
+		vendor:              false,
 	}
 	r.Options(opts...)
 	return r
@@ -139,6 +161,22 @@ func (o *trustedHostsOption) ApplyToReader(r *Reader) {
 	r.trustedHosts = o.trustedHosts
 }
 
+// WithTrustedKeys configures the [Reader] with a list of public keys, in the
+// authorized_keys format, that remote Taskfiles must be signed with. When set,
+// remote Taskfiles without a valid signature from one of them are rejected,
+// and signed ones don't prompt for confirmation.
+func WithTrustedKeys(trustedKeys []string) ReaderOption {
+	return &trustedKeysOption{trustedKeys: trustedKeys}
+}
+
+type trustedKeysOption struct {
+	trustedKeys []string
+}
+
+func (o *trustedKeysOption) ApplyToReader(r *Reader) {
+	r.trustedKeys = o.trustedKeys
+}
+
 // WithTempDir sets the temporary directory that will be used by the [Reader].
 // By default, the reader uses [os.TempDir].
 func WithTempDir(tempDir string) ReaderOption {
@@ -241,6 +279,91 @@ func (o *readerCertKeyOption) ApplyToReader(r *Reader) {
 	r.certKey = o.certKey
 }
 
+// WithReaderMirrors sets the URL prefixes of remote Taskfiles to replace with
+// the prefix of a mirror when downloading them.
+func WithReaderMirrors(mirrors map[string]string) ReaderOption {
+	return &readerMirrorsOption{mirrors: mirrors}
+}
+
+type readerMirrorsOption struct {
+	mirrors map[string]string
+}
+
+func (o *readerMirrorsOption) ApplyToReader(r *Reader) {
+	r.mirrors = o.mirrors
+}
+
+// WithReaderCredentials sets the credentials of the hosts of remote Taskfiles,
+// keyed by host, optionally followed by a port.
+func WithReaderCredentials(credentials map[string]Credentials) ReaderOption {
+	return &readerCredentialsOption{credentials: credentials}
+}
+
+type readerCredentialsOption struct {
+	credentials map[string]Credentials
+}
+
+func (o *readerCredentialsOption) ApplyToReader(r *Reader) {
+	r.credentials = o.credentials
+}
+
+// WithLockfile sets the path of the lock file used to pin remote Taskfiles. By
+// default, no lock file is used.
+func WithLockfile(lockfile string) ReaderOption {
+	return &lockfileOption{lockfile: lockfile}
+}
+
+type lockfileOption struct {
+	lockfile string
+}
+
+func (o *lockfileOption) ApplyToReader(r *Reader) {
+	r.lockfile = o.lockfile
+}
+
+// WithLockMode sets how the [Reader] uses the lock file. By default, remote
+// Taskfiles are only verified against it.
+func WithLockMode(mode LockMode) ReaderOption {
+	return &lockModeOption{mode: mode}
+}
+
+type lockModeOption struct {
+	mode LockMode
+}
+
+func (o *lockModeOption) ApplyToReader(r *Reader) {
+	r.lockMode = o.mode
+}
+
+// WithVendorDir sets the directory of the vendored copies of remote Taskfiles.
+// Vendored copies are used instead of the cache and the network, unless a
+// download is forced. By default, no vendored copies are used.
+func WithVendorDir(dir string) ReaderOption {
+	return &vendorDirOption{dir: dir}
+}
+
+type vendorDirOption struct {
+	dir string
+}
+
+func (o *vendorDirOption) ApplyToReader(r *Reader) {
+	r.vendorDir = o.dir
+}
+
+// WithVendor makes the [Reader] download every remote Taskfile and replace the
+// content of the vendor directory with them.
+func WithVendor(vendor bool) ReaderOption {
+	return &vendorOption{vendor: vendor}
+}
+
+type vendorOption struct {
+	vendor bool
+}
+
+func (o *vendorOption) ApplyToReader(r *Reader) {
+	r.vendor = o.vendor
+}
+
 // Read will read the Taskfile defined by the [Reader]'s [Node] and recurse
 // through any [ast.Includes] it finds, reading each included Taskfile and
 // building an [ast.TaskfileGraph] as it goes. If any errors occur, they will be
@@ -249,15 +372,151 @@ func (r *Reader) Read(ctx context.Context, node Node) (*ast.TaskfileGraph, error
 	// Clean up git cache after reading all taskfiles
 	defer func() {
 		_ = CleanGitCache()
+		cleanArchiveCache()
 	}()
 
+	if err := r.loadLockfile(); err != nil {
+		return nil, err
+	}
+
+	publicKeys, err := parseTrustedKeys(r.trustedKeys)
+	if err != nil {
+		return nil, err
+	}
+	r.publicKeys = publicKeys
+
+	if r.vendor {
+		if r.vendorDir == "" {
+			return nil, errors.New("task: no vendor directory set")
+		}
+		// Vendored copies are written to a new directory, which replaces the
+		// current one once every Taskfile was read
+		if err := os.RemoveAll(r.newVendorDir()); err != nil {
+			return nil, err
+		}
+		r.download = true
+	}
+
 	if err := r.include(ctx, node); err != nil {
 		return nil, err
 	}
 
+	if err := r.saveLockfile(); err != nil {
+		return nil, err
+	}
+
+	if err := r.saveVendorDir(); err != nil {
+		return nil, err
+	}
+
 	return r.graph, nil
 }
 
+func (r *Reader) newVendorDir() string {
+	return r.vendorDir + ".new"
+}
+
+func (r *Reader) saveVendorDir() error {
+	if !r.vendor {
+		return nil
+	}
+	r.debugf("writing vendored Taskfiles to %q\n", r.vendorDir)
+	// Create the directory even if there is nothing to vendor, so that it
+	// still replaces the current one
+	if err := os.MkdirAll(r.newVendorDir(), 0o755); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(r.vendorDir); err != nil {
+		return err
+	}
+	return os.Rename(r.newVendorDir(), r.vendorDir)
+}
+
+// loadLockfile reads the lock file, if any, and prepares a new one if it is
+// going to be written.
+func (r *Reader) loadLockfile() error {
+	if r.lockfile == "" {
+		return nil
+	}
+	if r.lockMode != LockModeUpdate {
+		lock, err := readLockfile(r.lockfile)
+		if err != nil {
+			return err
+		}
+		r.lock = lock
+	}
+	if r.lockMode != LockModeVerify {
+		r.newLock = newLockfile()
+		// Locked values can only be recorded from fresh copies
+		r.download = true
+	}
+	return nil
+}
+
+func (r *Reader) saveLockfile() error {
+	if r.newLock == nil {
+		return nil
+	}
+	r.debugf("writing lock file %q\n", r.lockfile)
+	return r.newLock.write(r.lockfile)
+}
+
+// lockedEntry returns the entry of the given node in the lock file, if any.
+func (r *Reader) lockedEntry(node RemoteNode) *LockEntry {
+	if r.lock == nil {
+		return nil
+	}
+	r.lockMutex.Lock()
+	defer r.lockMutex.Unlock()
+	return r.lock.Remotes[node.Location()]
+}
+
+// verifyLock verifies the content of the given node against the lock file and
+// records it in the lock file being written, if any.
+func (r *Reader) verifyLock(node RemoteNode, b []byte) error {
+	if r.lockfile == "" {
+		return nil
+	}
+	checksum, err := contentChecksum(node, b)
+	if err != nil {
+		return err
+	}
+	current := newLockEntry(node, checksum)
+	if r.lock != nil {
+		entry := r.lockedEntry(node)
+		switch {
+		case entry == nil && r.lockMode == LockModeVerify:
+			return &errors.TaskfileNotLockedError{
+				URI:      node.Location(),
+				Lockfile: r.lockfile,
+			}
+		case entry == nil:
+		case entry.Checksum != checksum:
+			return &errors.TaskfileDoesNotMatchChecksum{
+				URI:              node.Location(),
+				ExpectedChecksum: entry.Checksum,
+				ActualChecksum:   checksum,
+				Lockfile:         r.lockfile,
+			}
+		case entry.Commit != "" && current.Commit != "" && entry.Commit != current.Commit:
+			return &errors.TaskfileDoesNotMatchChecksum{
+				URI:              node.Location(),
+				ExpectedChecksum: entry.Commit,
+				ActualChecksum:   current.Commit,
+				Lockfile:         r.lockfile,
+			}
+		default:
+			current = entry
+		}
+	}
+	if r.newLock != nil {
+		r.lockMutex.Lock()
+		defer r.lockMutex.Unlock()
+		r.newLock.Remotes[node.Location()] = current
+	}
+	return nil
+}
+
 func (r *Reader) debugf(format string, a ...any) {
 	if r.debugFunc != nil {
 		r.debugFunc(fmt.Sprintf(format, a...))
@@ -326,11 +585,16 @@ func (r *Reader) include(ctx context.Context, node Node) error {
 		// Start a goroutine to process each included Taskfile
 		g.Go(func() error {
 			cache := &templater.Cache{Vars: vars}
+			var environ []string
+			if taskfileEnv := templater.ReplaceVars(vertex.Taskfile.Env, cache); taskfileEnv != nil {
+				environ = env.GetFromVars(taskfileEnv)
+			}
 			include = &ast.Include{
 				Namespace:      include.Namespace,
 				Taskfile:       templater.Replace(include.Taskfile, cache),
 				Dir:            templater.Replace(include.Dir, cache),
 				Optional:       include.Optional,
+				If:             templater.Replace(include.If, cache),
 				Internal:       include.Internal,
 				Flatten:        include.Flatten,
 				Aliases:        include.Aliases,
@@ -343,64 +607,30 @@ func (r *Reader) include(ctx context.Context, node Node) error {
 				return err
 			}
 
-			entrypoint, err := node.ResolveEntrypoint(include.Taskfile)
-			if err != nil {
-				return err
-			}
-
-			include.Dir, err = node.ResolveDir(include.Dir)
-			if err != nil {
-				return err
-			}
-
-			includeNode, err := NewNode(entrypoint, include.Dir, r.insecure,
-				WithParent(node),
-				WithChecksum(include.Checksum),
-				WithCACert(r.caCert),
-				WithCert(r.cert),
-				WithCertKey(r.certKey),
-			)
-			if err != nil {
-				if include.Optional {
+			// Skip the include entirely if its condition is not met. Other
+			// errors, like invalid commands, are returned.
+			if strings.TrimSpace(include.If) != "" {
+				err := execext.RunCommand(ctx, &execext.RunCommandOptions{
+					Command: include.If,
+					Dir:     node.Dir(),
+					Env:     environ,
+				})
+				var exitStatus interp.ExitStatus
+				if errors.As(err, &exitStatus) {
+					r.debugf("if condition not met - skipping include %q\n", include.Namespace)
 					return nil
 				}
-				return err
-			}
-
-			// Recurse into the included Taskfile
-			if err := r.include(ctx, includeNode); err != nil {
-				return err
+				if err != nil {
+					return fmt.Errorf("task: if condition of include %q: %w", include.Namespace, err)
+				}
 			}
 
-			// Create an edge between the Taskfiles
-			r.graph.Lock()
-			defer r.graph.Unlock()
-			edge, err := r.graph.Edge(node.Location(), includeNode.Location())
-			if err == graph.ErrEdgeNotFound {
-				// If the edge doesn't exist, create it
-				err = r.graph.AddEdge(
-					node.Location(),
-					includeNode.Location(),
-					graph.EdgeData([]*ast.Include{include}),
-					graph.EdgeWeight(1),
-				)
-			} else {
-				// If the edge already exists
-				edgeData := append(edge.Properties.Data.([]*ast.Include), include)
-				err = r.graph.UpdateEdge(
-					node.Location(),
-					includeNode.Location(),
-					graph.EdgeData(edgeData),
-					graph.EdgeWeight(len(edgeData)),
-				)
-			}
-			if errors.Is(err, graph.ErrEdgeCreatesCycle) {
-				return errors.TaskfileCycleError{
-					Source:      node.Location(),
-					Destination: includeNode.Location(),
-				}
+			// Globs can only be expanded on the local filesystem
+			_, isRemote := node.(RemoteNode)
+			if !isRemote && !isRemoteEntrypoint(include.Taskfile) && fsext.IsGlob(include.Taskfile) {
+				return r.includeGlob(ctx, node, include)
 			}
-			return err
+			return r.includeTaskfile(ctx, node, include)
 		})
 	}
 
@@ -408,6 +638,116 @@ func (r *Reader) include(ctx context.Context, node Node) error {
 	return g.Wait()
 }
 
+// includeGlob includes every Taskfile matching the glob pattern of the given
+// include, each in a namespace named after its directory, nested in the
+// namespace of the include. Unless the include sets a directory, the tasks of
+// each Taskfile run in its own directory.
+func (r *Reader) includeGlob(ctx context.Context, node Node, include *ast.Include) error {
+	pattern, err := node.ResolveEntrypoint(include.Taskfile)
+	if err != nil {
+		return err
+	}
+	paths, err := fsext.SearchGlob(pattern, DefaultTaskfiles)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 && !include.Optional {
+		return errors.TaskfileNotFoundError{URI: pattern}
+	}
+
+	names := make(map[string]string, len(paths))
+	for _, path := range paths {
+		// The pattern may match the including Taskfile itself
+		if path == node.Location() {
+			continue
+		}
+		name := filepath.Base(filepath.Dir(path))
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("task: Taskfiles %q and %q included by %q are in directories with the same name", other, path, include.Namespace)
+		}
+		names[name] = path
+
+		match := include.DeepCopy()
+		match.Namespace = include.Namespace + ast.NamespaceSeparator + name
+		match.Taskfile = path
+		for i, alias := range match.Aliases {
+			match.Aliases[i] = alias + ast.NamespaceSeparator + name
+		}
+		if match.Dir == "" {
+			match.Dir = filepath.Dir(path)
+		}
+		if err := r.includeTaskfile(ctx, node, match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// includeTaskfile reads the Taskfile of the given include and adds it to the
+// graph, with an edge from the given node.
+func (r *Reader) includeTaskfile(ctx context.Context, node Node, include *ast.Include) error {
+	entrypoint, err := node.ResolveEntrypoint(include.Taskfile)
+	if err != nil {
+		return err
+	}
+
+	include.Dir, err = node.ResolveDir(include.Dir)
+	if err != nil {
+		return err
+	}
+
+	includeNode, err := NewNode(entrypoint, include.Dir, r.insecure,
+		WithParent(node),
+		WithChecksum(include.Checksum),
+		WithCACert(r.caCert),
+		WithCert(r.cert),
+		WithCertKey(r.certKey),
+		WithMirrors(r.mirrors),
+		WithCredentials(r.credentials),
+	)
+	if err != nil {
+		if include.Optional {
+			return nil
+		}
+		return err
+	}
+
+	// Recurse into the included Taskfile
+	if err := r.include(ctx, includeNode); err != nil {
+		return err
+	}
+
+	// Create an edge between the Taskfiles
+	r.graph.Lock()
+	defer r.graph.Unlock()
+	edge, err := r.graph.Edge(node.Location(), includeNode.Location())
+	if err == graph.ErrEdgeNotFound {
+		// If the edge doesn't exist, create it
+		err = r.graph.AddEdge(
+			node.Location(),
+			includeNode.Location(),
+			graph.EdgeData([]*ast.Include{include}),
+			graph.EdgeWeight(1),
+		)
+	} else {
+		// If the edge already exists
+		edgeData := append(edge.Properties.Data.([]*ast.Include), include)
+		err = r.graph.UpdateEdge(
+			node.Location(),
+			includeNode.Location(),
+			graph.EdgeData(edgeData),
+			graph.EdgeWeight(len(edgeData)),
+		)
+	}
+	if errors.Is(err, graph.ErrEdgeCreatesCycle) {
+		return errors.TaskfileCycleError{
+			Source:      node.Location(),
+			Destination: includeNode.Location(),
+		}
+	}
+	return err
+}
+
 func (r *Reader) readNode(ctx context.Context, node Node) (*ast.Taskfile, error) {
 	b, err := r.readNodeContent(ctx, node)
 	if err != nil {
@@ -436,6 +776,20 @@ func (r *Reader) readNode(ctx context.Context, node Node) (*ast.Taskfile, error)
 
 	// Set the taskfile/task's locations
 	tf.Location = node.Location()
+	taskfileLocation := tf.Location
+	var commit string
+	switch node := node.(type) {
+	case *GitNode:
+		// Like archives, the tasks of git Taskfiles are located in the clone
+		// or the vendored copy of the repository, so that they can use its
+		// other files
+		commit = node.Commit()
+		taskfileLocation = cmp.Or(node.File(), tf.Location)
+	case *ArchiveNode:
+		// The tasks of archives are located in the extracted archive, so that
+		// they can use its other files
+		taskfileLocation = cmp.Or(node.File(), tf.Location)
+	}
 	for task := range tf.Tasks.Values(nil) {
 		// If the task is not defined, create a new one
 		if task == nil {
@@ -443,8 +797,9 @@ func (r *Reader) readNode(ctx context.Context, node Node) (*ast.Taskfile, error)
 		}
 		// Set the location of the taskfile for each task
 		if task.Location.Taskfile == "" {
-			task.Location.Taskfile = tf.Location
+			task.Location.Taskfile = taskfileLocation
 		}
+		task.Location.Commit = commit
 	}
 
 	return &tf, nil
@@ -475,6 +830,158 @@ func (r *Reader) readNodeContent(ctx context.Context, node Node) ([]byte, error)
 }
 
 func (r *Reader) readRemoteNodeContent(ctx context.Context, node RemoteNode) ([]byte, error) {
+	// Read git Taskfiles from the locked commit, so that moving refs are
+	// pinned as well
+	if gitNode, ok := node.(*GitNode); ok {
+		if entry := r.lockedEntry(node); entry != nil && entry.Commit != "" {
+			gitNode.lock(entry.Commit)
+		}
+		if r.tempDir != "" {
+			gitNode.setCache(filepath.Join(r.tempDir, gitCacheDirName), r.cacheExpiryDuration, r.download)
+		}
+	}
+
+	// Archives are extracted into the cache, as their tasks use the other
+	// files they contain. Local archives are extracted again by each run, like
+	// local Taskfiles are read again.
+	if archiveNode, ok := node.(*ArchiveNode); ok {
+		if r.tempDir != "" {
+			archiveNode.setCacheDir(filepath.Join(r.tempDir, archiveCacheDirName))
+		}
+		if archiveNode.local() {
+			return r.readLocalArchiveContent(ctx, archiveNode)
+		}
+	}
+
+	b, sig, err := r.readVendoredNodeContent(node)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		if b, sig, err = r.fetchRemoteNodeContent(ctx, node); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.verifyLock(node, b); err != nil {
+		return nil, err
+	}
+	if r.vendor {
+		vendored := NewVendorNode(node, r.newVendorDir())
+		r.debugf("vendoring %q to %q\n", node.Location(), vendored.Location())
+		if err := vendored.Write(b); err != nil {
+			return nil, err
+		}
+		if err := vendored.WriteSignature(sig); err != nil {
+			return nil, err
+		}
+		switch node := node.(type) {
+		case *GitNode:
+			err = node.writeFiles(ctx, vendored.FilesDir())
+		case *ArchiveNode:
+			err = node.writeFiles(vendored.FilesDir())
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// readLocalArchiveContent extracts the given local archive and returns the
+// Taskfile inside it.
+func (r *Reader) readLocalArchiveContent(ctx context.Context, node *ArchiveNode) ([]byte, error) {
+	b, err := node.ReadContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the given checksum doesn't match the sum pinned in the Taskfile
+	checksum, err := contentChecksum(node, b)
+	if err != nil {
+		return nil, err
+	}
+	if !node.Verify(checksum) {
+		return nil, &errors.TaskfileDoesNotMatchChecksum{
+			URI:              node.Location(),
+			ExpectedChecksum: node.Checksum(),
+			ActualChecksum:   checksum,
+		}
+	}
+	return b, nil
+}
+
+// contentChecksum returns the checksum of the given content of a node. For
+// archives, it is the checksum of the whole archive instead, as their tasks
+// use its other files too.
+func contentChecksum(node Node, b []byte) (string, error) {
+	if archiveNode, ok := node.(*ArchiveNode); ok {
+		return archiveNode.archiveChecksum()
+	}
+	return checksum(b), nil
+}
+
+// readVendoredNodeContent returns the vendored copy of the given node and its
+// signature, or nil if there is none or a download is forced.
+func (r *Reader) readVendoredNodeContent(node RemoteNode) ([]byte, []byte, error) {
+	if r.vendorDir == "" || r.download {
+		return nil, nil, nil
+	}
+	vendored := NewVendorNode(node, r.vendorDir)
+	b, err := vendored.Read()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	r.debugf("using vendored copy of %q at %q\n", node.Location(), vendored.Location())
+
+	// The tasks of git Taskfiles and archives use the vendored files of the
+	// repository or of the extracted archive
+	if filesNode, ok := node.(interface{ useVendored(dir string) error }); ok {
+		if err := filesNode.useVendored(vendored.FilesDir()); err != nil {
+			return nil, nil, fmt.Errorf(`task: the files of %q are not vendored, run "task --vendor" again: %w`, node.Location(), err)
+		}
+	}
+
+	// If the given checksum doesn't match the sum pinned in the Taskfile
+	checksum, err := contentChecksum(node, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !node.Verify(checksum) {
+		return nil, nil, &errors.TaskfileDoesNotMatchChecksum{
+			URI:              node.Location(),
+			ExpectedChecksum: node.Checksum(),
+			ActualChecksum:   checksum,
+		}
+	}
+
+	// If trusted keys are configured, the vendored copy must be signed by one
+	// of them too
+	sig := vendored.ReadSignature()
+	if len(r.publicKeys) > 0 {
+		if err := r.verifySignature(node, sig, b); err != nil {
+			return nil, nil, err
+		}
+	}
+	return b, sig, nil
+}
+
+// readCachedNodeContent returns the cached copy of the given node and its
+// signature, once verified if trusted keys are configured.
+func (r *Reader) readCachedNodeContent(node RemoteNode, cache *CacheNode, b []byte) ([]byte, []byte, error) {
+	sig := cache.ReadSignature()
+	if len(r.publicKeys) > 0 {
+		if err := r.verifySignature(node, sig, b); err != nil {
+			return nil, nil, err
+		}
+	}
+	restoreCommit(node, cache)
+	return b, sig, nil
+}
+
+func (r *Reader) fetchRemoteNodeContent(ctx context.Context, node RemoteNode) ([]byte, []byte, error) {
 	cache := NewCacheNode(node, r.tempDir)
 	now := time.Now().UTC()
 	timestamp := cache.ReadTimestamp()
@@ -490,7 +997,16 @@ func (r *Reader) readRemoteNodeContent(ctx context.Context, node RemoteNode) ([]
 		r.debugf("no cache found\n")
 		// If we couldn't find a cached copy, and we are offline, we can't do anything
 		if r.offline {
-			return nil, &errors.TaskfileCacheNotFoundError{
+			return nil, nil, &errors.TaskfileCacheNotFoundError{
+				URI: node.Location(),
+			}
+		}
+
+	// The cached Taskfile of an archive can't be used without its files
+	case err == nil && !isExtracted(node):
+		r.debugf("archive not extracted\n")
+		if r.offline {
+			return nil, nil, &errors.TaskfileCacheNotFoundError{
 				URI: node.Location(),
 			}
 		}
@@ -502,19 +1018,26 @@ func (r *Reader) readRemoteNodeContent(ctx context.Context, node RemoteNode) ([]
 		// If we can't fetch a fresh copy, we should use the cache anyway
 		if r.offline {
 			r.debugf("in offline mode, using expired cache\n")
-			return cachedBytes, nil
+			return r.readCachedNodeContent(node, cache, cachedBytes)
 		}
 
 	// Some other error
 	case err != nil:
-		return nil, err
+		return nil, nil, err
 
 	// Found valid cache
 	default:
 		r.debugf("cache found\n")
 		// Not being forced to redownload, return cache
 		if !r.download {
-			return cachedBytes, nil
+			b, sig, err := r.readCachedNodeContent(node, cache, cachedBytes)
+			// A copy cached before the keys were trusted is downloaded again
+			// with its signature
+			var sigErr *errors.TaskfileSignatureError
+			if !errors.As(err, &sigErr) || r.offline {
+				return b, sig, err
+			}
+			r.debugf("cached copy is not signed by a trusted key: %v\n", err)
 		}
 		cacheFound = true
 	}
@@ -530,25 +1053,39 @@ func (r *Reader) readRemoteNodeContent(ctx context.Context, node RemoteNode) ([]
 			} else {
 				r.debugf("failed to fetch remote file: %s: using expired cache\n", ctx.Err().Error())
 			}
-			return cachedBytes, nil
+			return r.readCachedNodeContent(node, cache, cachedBytes)
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
 	r.debugf("found remote file at %q\n", node.Location())
 
 	// If the given checksum doesn't match the sum pinned in the Taskfile
-	checksum := checksum(downloadedBytes)
+	checksum, err := contentChecksum(node, downloadedBytes)
+	if err != nil {
+		return nil, nil, err
+	}
 	if !node.Verify(checksum) {
-		return nil, &errors.TaskfileDoesNotMatchChecksum{
+		return nil, nil, &errors.TaskfileDoesNotMatchChecksum{
 			URI:              node.Location(),
 			ExpectedChecksum: node.Checksum(),
 			ActualChecksum:   checksum,
 		}
 	}
 
-	// If there is no manual checksum pin, run the automatic checks
-	if node.Checksum() == "" {
+	// If trusted keys are configured, the Taskfile must be signed by one of
+	// them, in which case it is trusted
+	var sig []byte
+	if len(r.publicKeys) > 0 {
+		if sig, err = r.readSignature(ctx, node, downloadedBytes); err != nil {
+			return nil, nil, err
+		}
+	}
+	signed := sig != nil
+
+	// If there is no manual checksum pin, nor a matching one in the lock file,
+	// run the automatic checks
+	if entry := r.lockedEntry(node); !signed && node.Checksum() == "" && (entry == nil || entry.Checksum != checksum) {
 		// Prompt the user if required (unless host is trusted)
 		prompt := cache.ChecksumPrompt(checksum)
 		if prompt != "" && !r.isTrusted(node.Location()) {
@@ -557,26 +1094,63 @@ func (r *Reader) readRemoteNodeContent(ctx context.Context, node RemoteNode) ([]
 				defer r.promptMutex.Unlock()
 				return r.promptf(prompt, node.Location())
 			}(); err != nil {
-				return nil, &errors.TaskfileNotTrustedError{URI: node.Location()}
+				return nil, nil, &errors.TaskfileNotTrustedError{URI: node.Location()}
 			}
 		}
 	}
 
 	// Store the checksum
 	if err := cache.WriteChecksum(checksum); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Store the timestamp
 	if err := cache.WriteTimestamp(now); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// Store the URL, as it can't be derived from the cache key
+	if err := cache.WriteURL(node.Location()); err != nil {
+		return nil, nil, err
+	}
+
+	// Store the commit of git Taskfiles, to expose it when they are read from
+	// the cache
+	if gitNode, ok := node.(*GitNode); ok {
+		if err := cache.WriteCommit(gitNode.Commit()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Store the signature, to verify the cached copy when it is read again
+	if err := cache.WriteSignature(sig); err != nil {
+		return nil, nil, err
 	}
 
 	// Cache the file
 	r.debugf("caching %q to %q\n", node.Location(), cache.Location())
 	if err = cache.Write(downloadedBytes); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return downloadedBytes, nil
+	return downloadedBytes, sig, nil
+}
+
+// isExtracted reports whether the given node is extracted, if it is an archive.
+func isExtracted(node RemoteNode) bool {
+	archiveNode, ok := node.(*ArchiveNode)
+	return !ok || archiveNode.extracted()
+}
+
+// restoreCommit sets the commit and the file of git nodes read from the
+// cache, as they are not read from the repository.
+func restoreCommit(node RemoteNode, cache *CacheNode) {
+	gitNode, ok := node.(*GitNode)
+	if !ok {
+		return
+	}
+	if gitNode.commit == "" {
+		gitNode.commit = cache.ReadCommit()
+	}
+	gitNode.restoreFile()
 }
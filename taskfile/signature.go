@@ -0,0 +1,108 @@
+package taskfile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/internal/execext"
+	"github.com/go-task/task/v3/internal/sshsig"
+)
+
+const (
+	// SignatureExt is the extension of the detached signatures of remote
+	// Taskfiles, which are stored next to them.
+	SignatureExt = ".sig"
+	// signatureNamespace is the namespace of the signatures, as used by
+	// "ssh-keygen -Y sign -n task".
+	signatureNamespace = "task"
+)
+
+// Sign signs the given file with the SSH private key at keyPath, and writes the
+// signature next to it. It returns the path of the signature.
+func Sign(path string, keyPath string) (string, error) {
+	keyPath, err := execext.ExpandLiteral(keyPath)
+	if err != nil {
+		return "", err
+	}
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		return "", err
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	var passphraseErr *ssh.PassphraseMissingError
+	if errors.As(err, &passphraseErr) {
+		return "", fmt.Errorf(`task: the key %q is encrypted, use "ssh-keygen -Y sign -n task -f %s %s" instead`, keyPath, keyPath, path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("task: failed to parse the key %q: %w", keyPath, err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sig, err := sshsig.Sign(signer, signatureNamespace, b)
+	if err != nil {
+		return "", err
+	}
+	sigPath := path + SignatureExt
+	if err := os.WriteFile(sigPath, sig, 0o644); err != nil {
+		return "", err
+	}
+	return sigPath, nil
+}
+
+// parseTrustedKeys parses public keys in the authorized_keys format.
+func parseTrustedKeys(keys []string) ([]ssh.PublicKey, error) {
+	publicKeys := make([]ssh.PublicKey, 0, len(keys))
+	for _, key := range keys {
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("task: invalid trusted key %q: %w", key, err)
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	return publicKeys, nil
+}
+
+// readSignature reads the signature of the given remote node and verifies
+// that its content is signed by one of the trusted keys.
+func (r *Reader) readSignature(ctx context.Context, node RemoteNode, b []byte) ([]byte, error) {
+	sig, err := node.ReadSignature(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.verifySignature(node, sig, b); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// verifySignature verifies that the content of the given remote node is signed
+// by one of the trusted keys, using the given signature. It is used for the
+// cached and vendored copies too, which store the signature next to them.
+func (r *Reader) verifySignature(node RemoteNode, sig, b []byte) error {
+	if sig == nil {
+		return &errors.TaskfileSignatureError{URI: node.Location(), Reason: errors.SignatureMissing}
+	}
+	publicKey, err := sshsig.Verify(sig, signatureNamespace, b)
+	if err != nil {
+		return &errors.TaskfileSignatureError{URI: node.Location(), Reason: errors.SignatureInvalid}
+	}
+	if !slices.ContainsFunc(r.publicKeys, func(key ssh.PublicKey) bool {
+		return sshsig.Equal(key, publicKey)
+	}) {
+		return &errors.TaskfileSignatureError{
+			URI:    node.Location(),
+			Reason: errors.SignatureUntrusted,
+			Key:    ssh.FingerprintSHA256(publicKey),
+		}
+	}
+	r.debugf("%q is signed by %s\n", node.Location(), ssh.FingerprintSHA256(publicKey))
+	return nil
+}
@@ -19,19 +19,41 @@ type TaskRC struct {
 	Interactive  *bool           `yaml:"interactive"`
 	Remote       Remote          `yaml:"remote"`
 	Failfast     bool            `yaml:"failfast"`
+	TaskTimeout  *time.Duration  `yaml:"task-timeout"`
+	BuildCache   BuildCache      `yaml:"build-cache"`
 	Experiments  map[string]int  `yaml:"experiments"`
 }
 
 type Remote struct {
-	Insecure     *bool          `yaml:"insecure"`
-	Offline      *bool          `yaml:"offline"`
-	Timeout      *time.Duration `yaml:"timeout"`
-	CacheExpiry  *time.Duration `yaml:"cache-expiry"`
-	CacheDir     *string        `yaml:"cache-dir"`
-	TrustedHosts []string       `yaml:"trusted-hosts"`
-	CACert       *string        `yaml:"cacert"`
-	Cert         *string        `yaml:"cert"`
-	CertKey      *string        `yaml:"cert-key"`
+	Insecure     *bool                 `yaml:"insecure"`
+	Offline      *bool                 `yaml:"offline"`
+	Timeout      *time.Duration        `yaml:"timeout"`
+	CacheExpiry  *time.Duration        `yaml:"cache-expiry"`
+	CacheDir     *string               `yaml:"cache-dir"`
+	TrustedHosts []string              `yaml:"trusted-hosts"`
+	TrustedKeys  []string              `yaml:"trusted-keys"`
+	SigningKey   *string               `yaml:"signing-key"`
+	Mirrors      map[string]string     `yaml:"mirrors"`
+	Auth         map[string]RemoteAuth `yaml:"auth"`
+	CACert       *string               `yaml:"cacert"`
+	Cert         *string               `yaml:"cert"`
+	CertKey      *string               `yaml:"cert-key"`
+}
+
+// RemoteAuth holds the credentials of a host of remote Taskfiles. Environment
+// variables in the values are expanded, so that secrets don't have to be
+// written in the config file.
+type RemoteAuth struct {
+	Username string            `yaml:"username"`
+	Password string            `yaml:"password"`
+	Token    string            `yaml:"token"`
+	Headers  map[string]string `yaml:"headers"`
+}
+
+type BuildCache struct {
+	Dir   *string `yaml:"dir"`
+	URL   *string `yaml:"url"`
+	Token *string `yaml:"token"`
 }
 
 // Merge combines the current TaskRC with another TaskRC, prioritizing non-nil fields from the other TaskRC.
@@ -59,6 +81,24 @@ func (t *TaskRC) Merge(other *TaskRC) {
 		slices.Sort(merged)
 		t.Remote.TrustedHosts = slices.Compact(merged)
 	}
+	if len(other.Remote.TrustedKeys) > 0 {
+		merged := slices.Concat(other.Remote.TrustedKeys, t.Remote.TrustedKeys)
+		slices.Sort(merged)
+		t.Remote.TrustedKeys = slices.Compact(merged)
+	}
+	if len(other.Remote.Mirrors) > 0 {
+		if t.Remote.Mirrors == nil {
+			t.Remote.Mirrors = map[string]string{}
+		}
+		maps.Copy(t.Remote.Mirrors, other.Remote.Mirrors)
+	}
+	if len(other.Remote.Auth) > 0 {
+		if t.Remote.Auth == nil {
+			t.Remote.Auth = map[string]RemoteAuth{}
+		}
+		maps.Copy(t.Remote.Auth, other.Remote.Auth)
+	}
+	t.Remote.SigningKey = cmp.Or(other.Remote.SigningKey, t.Remote.SigningKey)
 	t.Remote.CACert = cmp.Or(other.Remote.CACert, t.Remote.CACert)
 	t.Remote.Cert = cmp.Or(other.Remote.Cert, t.Remote.Cert)
 	t.Remote.CertKey = cmp.Or(other.Remote.CertKey, t.Remote.CertKey)
@@ -70,4 +110,8 @@ func (t *TaskRC) Merge(other *TaskRC) {
 	t.Concurrency = cmp.Or(other.Concurrency, t.Concurrency)
 	t.Interactive = cmp.Or(other.Interactive, t.Interactive)
 	t.Failfast = cmp.Or(other.Failfast, t.Failfast)
+	t.TaskTimeout = cmp.Or(other.TaskTimeout, t.TaskTimeout)
+	t.BuildCache.Dir = cmp.Or(other.BuildCache.Dir, t.BuildCache.Dir)
+	t.BuildCache.URL = cmp.Or(other.BuildCache.URL, t.BuildCache.URL)
+	t.BuildCache.Token = cmp.Or(other.BuildCache.Token, t.BuildCache.Token)
 }
@@ -306,4 +306,50 @@ remote:
 		assert.Equal(t, &cacheExpiry, base.Remote.CacheExpiry)
 		assert.Equal(t, []string{"github.com", "gitlab.com"}, base.Remote.TrustedHosts)
 	})
+
+	t.Run("mirrors merge", func(t *testing.T) { //nolint:paralleltest // parent test cannot run in parallel
+		base := &ast.TaskRC{
+			Remote: ast.Remote{
+				Mirrors: map[string]string{
+					"https://github.com/": "https://global.example.com/",
+					"https://gitlab.com/": "https://gitlab.example.com/",
+				},
+			},
+		}
+		other := &ast.TaskRC{
+			Remote: ast.Remote{
+				Mirrors: map[string]string{"https://github.com/": "https://local.example.com/"},
+			},
+		}
+
+		base.Merge(other)
+
+		assert.Equal(t, map[string]string{
+			"https://github.com/": "https://local.example.com/",
+			"https://gitlab.com/": "https://gitlab.example.com/",
+		}, base.Remote.Mirrors)
+	})
+
+	t.Run("auth merge", func(t *testing.T) { //nolint:paralleltest // parent test cannot run in parallel
+		base := &ast.TaskRC{
+			Remote: ast.Remote{
+				Auth: map[string]ast.RemoteAuth{
+					"github.com": {Token: "${GLOBAL_TOKEN}"},
+					"gitlab.com": {Username: "ci", Password: "${GITLAB_PASSWORD}"},
+				},
+			},
+		}
+		other := &ast.TaskRC{
+			Remote: ast.Remote{
+				Auth: map[string]ast.RemoteAuth{"github.com": {Token: "${LOCAL_TOKEN}"}},
+			},
+		}
+
+		base.Merge(other)
+
+		assert.Equal(t, map[string]ast.RemoteAuth{
+			"github.com": {Token: "${LOCAL_TOKEN}"},
+			"gitlab.com": {Username: "ci", Password: "${GITLAB_PASSWORD}"},
+		}, base.Remote.Auth)
+	})
 }
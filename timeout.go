@@ -0,0 +1,46 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-task/task/v3/errors"
+	"github.com/go-task/task/v3/taskfile/ast"
+)
+
+// withTimeout returns a context that is cancelled with the given timeout error
+// as its cause once the timeout expires. A zero timeout disables the timeout.
+func withTimeout(ctx context.Context, timeout time.Duration, timeoutErr *errors.TaskTimeoutError) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	timeoutErr.Timeout = timeout
+	return context.WithTimeoutCause(ctx, timeout, timeoutErr)
+}
+
+// timeoutError replaces the given error with a [errors.TaskTimeoutError] if
+// the context was cancelled because its timeout expired.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var timeoutErr *errors.TaskTimeoutError
+	if errors.As(context.Cause(ctx), &timeoutErr) {
+		return timeoutErr
+	}
+	return err
+}
+
+// hasDeadline returns true if the context will be cancelled by a timeout.
+func hasDeadline(ctx context.Context) bool {
+	_, ok := ctx.Deadline()
+	return ok
+}
+
+// cmdName returns a short description of the command for error messages.
+func cmdName(cmd *ast.Cmd) string {
+	if cmd.Task != "" {
+		return "task: " + cmd.Task
+	}
+	return cmd.Cmd
+}
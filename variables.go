@@ -1,6 +1,7 @@
 package task
 
 import (
+	"cmp"
 	"fmt"
 	"maps"
 	"os"
@@ -74,6 +75,11 @@ func (e *Executor) CompiledTaskForTaskList(call *Call) (*ast.Task, error) {
 		Watch:                origTask.Watch,
 		Namespace:            origTask.Namespace,
 		Failfast:             origTask.Failfast,
+		Retries:              origTask.Retries,
+		Timeout:              origTask.Timeout,
+		Cache:                origTask.Cache,
+		Fingerprint:          origTask.Fingerprint,
+		IgnoreFiles:          deepcopy.Scalar(origTask.IgnoreFiles),
 	}, nil
 }
 
@@ -131,6 +137,11 @@ func (e *Executor) compiledTask(call *Call, evaluateShVars bool) (*ast.Task, err
 		Requires:             origTask.Requires,
 		Watch:                origTask.Watch,
 		Failfast:             origTask.Failfast,
+		Retries:              origTask.Retries,
+		Timeout:              origTask.Timeout,
+		Cache:                origTask.Cache,
+		Fingerprint:          origTask.Fingerprint,
+		IgnoreFiles:          deepcopy.Scalar(origTask.IgnoreFiles),
 		Namespace:            origTask.Namespace,
 		FullName:             fullName,
 	}
@@ -183,12 +194,21 @@ func (e *Executor) compiledTask(call *Call, evaluateShVars bool) (*ast.Task, err
 		}
 	}
 
+	// The timestamp method only compares the times of the sources and
+	// generates, so it can't tell apart the variants of the fingerprint
+	if new.Fingerprint != nil && cmp.Or(new.Method, e.Taskfile.Method) == "timestamp" {
+		return nil, fmt.Errorf(`task: Task %q can't use "fingerprint" with the "timestamp" method`, origTask.Task)
+	}
+
 	if len(origTask.Sources) > 0 && origTask.Method != "none" {
 		var checker fingerprint.SourcesCheckable
 
-		if origTask.Method == "timestamp" {
+		switch origTask.Method {
+		case "timestamp":
 			checker = fingerprint.NewTimestampChecker(e.TempDir.Fingerprint, e.Dry)
-		} else {
+		case "git":
+			checker = fingerprint.NewGitChecker(e.TempDir.Fingerprint, e.Dry)
+		default:
 			checker = fingerprint.NewChecksumChecker(e.TempDir.Fingerprint, e.Dry)
 		}
 
@@ -210,7 +230,7 @@ func (e *Executor) compiledTask(call *Call, evaluateShVars bool) (*ast.Task, err
 				continue
 			}
 			if cmd.For != nil {
-				list, keys, err := itemsFromFor(cmd.For, new.Dir, new.Sources, new.Generates, vars, origTask.Location, cache)
+				list, keys, err := itemsFromFor(cmd.For, &new, vars, origTask.Location, cache)
 				if err != nil {
 					return nil, err
 				}
@@ -259,7 +279,7 @@ func (e *Executor) compiledTask(call *Call, evaluateShVars bool) (*ast.Task, err
 				continue
 			}
 			if dep.For != nil {
-				list, keys, err := itemsFromFor(dep.For, new.Dir, new.Sources, new.Generates, vars, origTask.Location, cache)
+				list, keys, err := itemsFromFor(dep.For, &new, vars, origTask.Location, cache)
 				if err != nil {
 					return nil, err
 				}
@@ -327,9 +347,7 @@ func asAnySlice[T any](slice []T) []any {
 
 func itemsFromFor(
 	f *ast.For,
-	dir string,
-	sources []*ast.Glob,
-	generates []*ast.Glob,
+	t *ast.Task,
 	vars *ast.Vars,
 	location *ast.Location,
 	cache *templater.Cache,
@@ -352,13 +370,13 @@ func itemsFromFor(
 	}
 	// Get the list from the task sources
 	if f.From == "sources" {
-		glist, err := fingerprint.Globs(dir, sources)
+		glist, err := fingerprint.Sources(t)
 		if err != nil {
 			return nil, nil, err
 		}
 		// Make the paths relative to the task dir
 		for i, v := range glist {
-			if glist[i], err = filepath.Rel(dir, v); err != nil {
+			if glist[i], err = filepath.Rel(t.Dir, v); err != nil {
 				return nil, nil, err
 			}
 		}
@@ -366,13 +384,13 @@ func itemsFromFor(
 	}
 	// Get the list from the task generates
 	if f.From == "generates" {
-		glist, err := fingerprint.Globs(dir, generates)
+		glist, err := fingerprint.Globs(t.Dir, t.Generates)
 		if err != nil {
 			return nil, nil, err
 		}
 		// Make the paths relative to the task dir
 		for i, v := range glist {
-			if glist[i], err = filepath.Rel(dir, v); err != nil {
+			if glist[i], err = filepath.Rel(t.Dir, v); err != nil {
 				return nil, nil, err
 			}
 		}